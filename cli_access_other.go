@@ -0,0 +1,15 @@
+//go:build !unix
+
+package cli
+
+// canAccess reports whether the current user may read a file, or list a directory.
+// Without access checks, errors are only shown when a directory is opened.
+func canAccess(path string, isDir bool) bool {
+	return true
+}
+
+// canWrite reports whether the current user may create files in dir.
+// Without access checks, errors are only shown when a file is written.
+func canWrite(dir string) bool {
+	return true
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package cli
+
+import "golang.org/x/sys/unix"
+
+// canAccess reports whether the current user may read a file, or list a directory
+func canAccess(path string, isDir bool) bool {
+	mode := uint32(unix.R_OK)
+	if isDir {
+		mode |= unix.X_OK
+	}
+	return unix.Access(path, mode) == nil
+}
+
+// canWrite reports whether the current user may create files in dir
+func canWrite(dir string) bool {
+	return unix.Access(dir, unix.W_OK|unix.X_OK) == nil
+}
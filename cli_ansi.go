@@ -0,0 +1,135 @@
+package cli
+
+import "strings"
+
+// escapeLen returns the length of the ANSI escape sequence at the start of s, or 0
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\033' {
+		return 0
+	}
+
+	switch s[1] {
+	case '[':
+		// CSI sequences, e.g. SGR colors: ESC [ params final-byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+		}
+		return len(s)
+
+	case ']', 'P', '_', '^':
+		// OSC, DCS, APC and PM strings, e.g. hyperlinks: ESC ] 8 ; ; url BEL
+		// They are terminated by BEL or ST (ESC \)
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+
+	return 2
+}
+
+// StripANSI removes all ANSI escape sequences (colors, styles, hyperlinks) from s
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\033") {
+		return s
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		sb.WriteByte(s[i])
+		i++
+	}
+	return sb.String()
+}
+
+// VisibleWidth returns the number of terminal columns s occupies.
+// Escape sequences are ignored and wide characters count as two columns.
+func VisibleWidth(s string) int {
+	return stringWidth(StripANSI(s))
+}
+
+// truncate cuts s to at most width columns, replacing the cut-off tail with an ellipsis.
+// Escape sequences are kept, so styles are reset and hyperlinks closed as in s.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if VisibleWidth(s) <= width {
+		return s
+	}
+
+	var sb strings.Builder
+	used := 0
+	cut := false
+
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			sb.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+
+		n := graphemeLen(s[i:])
+		w := graphemeWidth(s[i : i+n])
+		i += n
+
+		if cut {
+			continue
+		}
+
+		if used+w > width-1 {
+			sb.WriteString(ellipsis)
+			cut = true
+			continue
+		}
+
+		sb.WriteString(s[i-n : i])
+		used += w
+	}
+
+	return sb.String()
+}
+
+// padRight cuts or pads s with spaces to exactly width columns
+func padRight(s string, width int) string {
+	s = truncate(s, width)
+	if w := VisibleWidth(s); w < width {
+		s += strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// truncateLeft cuts plain text s to at most width columns, keeping its end.
+// It is used for text the cursor sits behind, like the buffer of an input.
+func truncateLeft(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if stringWidth(s) <= width {
+		return s
+	}
+
+	clusters := graphemes(s)
+
+	start := len(clusters)
+	for used := 0; start > 0; start-- {
+		w := graphemeWidth(clusters[start-1])
+		if used+w > width-1 {
+			break
+		}
+		used += w
+	}
+
+	return ellipsis + strings.Join(clusters[start:], "")
+}
@@ -0,0 +1,58 @@
+package cli
+
+import "testing"
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "plain", want: "plain"},
+		{s: "\033[1;31mred\033[0m", want: "red"},
+		{s: "\033[38;2;1;2;3mtrue\033[0m color", want: "true color"},
+		{s: "\033]8;;https://example.com\033\\link\033]8;;\033\\", want: "link"},
+		{s: "\033]0;title\a text", want: " text"},
+		{s: "a\033[2Jb", want: "ab"},
+	}
+
+	for _, tt := range tests {
+		if got := StripANSI(tt.s); got != tt.want {
+			t.Errorf("StripANSI(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{s: "hello", width: 10, want: "hello"},
+		{s: "hello", width: 5, want: "hello"},
+		{s: "hello", width: 4, want: "hel…"},
+		{s: "hello", width: 1, want: "…"},
+		{s: "hello", width: 0, want: ""},
+
+		// Wide characters are not cut in half
+		{s: "日本語", width: 5, want: "日本…"},
+		{s: "日本語", width: 4, want: "日…"},
+
+		// Grapheme clusters are kept whole
+		{s: "e\u0301e\u0301e\u0301", width: 2, want: "e\u0301…"},
+		{s: "❤\ufe0f❤\ufe0f❤\ufe0f", width: 3, want: "❤\ufe0f…"},
+
+		// Escape sequences are kept, so styles are still reset
+		{s: "\033[31mhello\033[0m", width: 4, want: "\033[31mhel…\033[0m"},
+	}
+
+	for _, tt := range tests {
+		got := truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if w := VisibleWidth(got); w > tt.width {
+			t.Errorf("truncate(%q, %d) is %d columns wide", tt.s, tt.width, w)
+		}
+	}
+}
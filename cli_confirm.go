@@ -3,25 +3,148 @@ package cli
 import (
 	"fmt"
 	"os"
+	"unicode"
+	"unicode/utf8"
 )
 
-func Confirm(label string, defaultValue bool) (bool, error) {
+// ConfirmOption configures a Confirm prompt
+type ConfirmOption func(*confirmOptions)
+
+type confirmOptions struct {
+	yes string
+	no  string
+
+	toggle bool
+	danger bool
+}
+
+// ConfirmLabels replaces "yes" and "no" with custom choices, e.g. "Deploy" and "Cancel".
+// Besides y and n, the first letter of each label selects it.
+func ConfirmLabels(yes, no string) ConfirmOption {
+	return func(o *confirmOptions) {
+		o.yes = yes
+		o.no = no
+	}
+}
+
+// ConfirmToggle renders both choices side by side, selectable with the arrow keys
+func ConfirmToggle() ConfirmOption {
+	return func(o *confirmOptions) {
+		o.toggle = true
+	}
+}
+
+// ConfirmDanger styles the prompt for destructive operations
+func ConfirmDanger() ConfirmOption {
+	return func(o *confirmOptions) {
+		o.danger = true
+	}
+}
+
+// shortcut returns the lowercase first letter of a choice label
+func shortcut(label string) rune {
+	r, _ := utf8.DecodeRuneInString(label)
+	return unicode.ToLower(r)
+}
+
+func Confirm(label string, defaultValue bool, options ...ConfirmOption) (bool, error) {
+	return confirm(promptContext{}, label, defaultValue, options...)
+}
+
+// confirm asks a yes/no question
+func confirm(ctx promptContext, label string, defaultValue bool, options ...ConfirmOption) (bool, error) {
+	o := &confirmOptions{
+		yes: "yes",
+		no:  "no",
+	}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	styleLabel := themeAccent
+	styleYes := themeSuccess
+	styleNo := themeError
+
+	if o.danger {
+		styleLabel = themeError
+		styleYes = themeError
+		styleNo = themeMuted
+	}
+
+	// Accept y/n as well as the first letters of custom labels, unless they are ambiguous
+	yesKey, noKey := shortcut(o.yes), shortcut(o.no)
+	if yesKey == noKey || yesKey == 'n' || noKey == 'y' {
+		yesKey, noKey = 'y', 'n'
+	}
+
 	var result bool
 
+	screen := ctx.frame()
+
 	err := withRawMode(func() error {
+		selected := defaultValue
+
+		keys := newKeyReader(os.Stdin)
+		defer keys.Close()
+
+		if o.toggle {
+			fmt.Print(escHideCursor)
+			defer fmt.Print(escShowCursor)
+		}
+
+		prompt := ""
+
 		redraw := func() {
-			fmt.Print("\r\033[K")
-			hint := "(y/N)"
-			if defaultValue {
-				hint = "(Y/n)"
+			prompt = styleLabel(bold(label)) + " "
+
+			switch {
+			case o.toggle:
+				if selected {
+					prompt += bold(styleYes("▸ "+o.yes)) + "   " + themeMuted(o.no)
+				} else {
+					prompt += themeMuted(o.yes) + "   " + bold(styleNo("▸ "+o.no))
+				}
+
+			case o.yes == "yes" && o.no == "no":
+				hint := "(y/N)"
+				if defaultValue {
+					hint = "(Y/n)"
+				}
+				prompt += themeSubtle(hint) + styleLabel(": ")
+
+			default:
+				yes, no := themeSubtle(o.yes), themeSubtle(o.no)
+				if defaultValue {
+					yes = bold(themeText(o.yes))
+				} else {
+					no = bold(themeText(o.no))
+				}
+				prompt += themeSubtle("(") + yes + themeSubtle("/") + no + themeSubtle(")") + styleLabel(": ")
+			}
+
+			screen.inline(prompt)
+		}
+
+		finish := func(value bool) {
+			result = value
+
+			if o.toggle {
+				prompt = styleLabel(bold(label)) + " "
+			}
+
+			screen.clear()
+			if value {
+				screen.line(prompt + styleYes(o.yes))
+			} else {
+				screen.line(prompt + styleNo(o.no))
 			}
-			fmt.Print(themeAccent(bold(label)) + " " + themeSubtle(hint) + themeAccent(": "))
 		}
 
 		redraw()
 
 		for {
-			key, char, err := readKey(os.Stdin)
+			key, char, err := keys.Read()
 			if err != nil {
 				return err
 			}
@@ -32,26 +155,118 @@ func Confirm(label string, defaultValue bool) (bool, error) {
 				return ErrUserAborted
 
 			case keyEnter:
-				result = defaultValue
-				if result {
-					fmt.Print(themeSuccess("yes") + "\r\n")
+				if o.toggle {
+					finish(selected)
 				} else {
-					fmt.Print(themeError("no") + "\r\n")
+					finish(defaultValue)
 				}
 				return nil
 
+			case keyLeft, keyRight, keyTab:
+				if o.toggle {
+					selected = !selected
+				}
+
+			case keyEscape, keyShiftTab:
+				if ctx.back {
+					screen.clear()
+					return errBack
+				}
+
 			default:
-				if char == 'y' || char == 'Y' {
-					result = true
-					fmt.Print(themeSuccess("yes") + "\r\n")
+				switch unicode.ToLower(char) {
+				case 'y', yesKey:
+					finish(true)
 					return nil
-				}
-				if char == 'n' || char == 'N' {
-					result = false
-					fmt.Print(themeError("no") + "\r\n")
+				case 'n', noKey:
+					finish(false)
 					return nil
 				}
 			}
+
+			screen.clear()
+			redraw()
+		}
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return result, nil
+}
+
+func MustConfirm(label string, defaultValue bool, options ...ConfirmOption) bool {
+	value, err := Confirm(label, defaultValue, options...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return value
+}
+
+// ConfirmTyped asks the user to type expected, e.g. the name of a resource about to be deleted.
+// It returns true only if the input matches expected exactly.
+func ConfirmTyped(label, expected string) (bool, error) {
+	var result bool
+
+	err := withRawMode(func() error {
+		buffer := ""
+		screen := &frame{}
+
+		keys := newKeyReader(os.Stdin)
+		defer keys.Close()
+
+		redraw := func() {
+			prompt := themeError(bold(label)) + " " + themeSubtle("(type ") + themeText(expected) + themeSubtle(" to confirm)") + themeError(": ")
+
+			width, _ := Size()
+			text := truncateLeft(buffer, width-1-VisibleWidth(prompt))
+
+			if buffer == expected {
+				screen.inline(prompt + themeError(text))
+			} else {
+				screen.inline(prompt + themeText(text))
+			}
+		}
+
+		redraw()
+
+		for {
+			key, char, err := keys.Read()
+			if err != nil {
+				return err
+			}
+
+			switch key {
+			case keyCtrlC:
+				fmt.Print("\r\n")
+				return ErrUserAborted
+
+			case keyEnter:
+				result = buffer == expected
+
+				if !result && buffer != "" {
+					fmt.Print(" " + themeMuted("(does not match)"))
+				}
+				fmt.Print("\r\n")
+				return nil
+
+			case keyBackspace:
+				buffer = trimLastGrapheme(buffer)
+
+			case keyCtrlU:
+				buffer = ""
+
+			default:
+				if char != 0 && char >= 32 {
+					buffer += string(char)
+				}
+			}
+
+			screen.clear()
+			redraw()
 		}
 	})
 
@@ -62,8 +277,8 @@ func Confirm(label string, defaultValue bool) (bool, error) {
 	return result, nil
 }
 
-func MustConfirm(label string, defaultValue bool) bool {
-	value, err := Confirm(label, defaultValue)
+func MustConfirmTyped(label, expected string) bool {
+	value, err := ConfirmTyped(label, expected)
 
 	if err != nil {
 		Fatal(err)
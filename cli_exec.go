@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ExecOption configures Exec
+type ExecOption func(*execOptions)
+
+type execOptions struct {
+	tail int
+}
+
+// ExecTail sets the number of output lines shown below the spinner, 5 by default
+func ExecTail(lines int) ExecOption {
+	return func(o *execOptions) {
+		o.tail = lines
+	}
+}
+
+// execOutput captures the combined output of a command and keeps its last lines for the task
+type execOutput struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	tail []string
+
+	// partial is the last line, as long as it is not terminated
+	partial string
+
+	size int
+	task *Task
+
+	// plain writes every line to the log as well, as there is no spinner to show the tail
+	plain bool
+}
+
+func (o *execOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.buf.Write(p)
+
+	text := o.partial + string(p)
+	lines := strings.Split(text, "\n")
+
+	o.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		o.push(line)
+	}
+
+	// The unterminated line is shown as well, e.g. a progress indicator
+	tail := append([]string(nil), o.tail...)
+	if line := execLine(o.partial); line != "" {
+		tail = append(tail, line)
+	}
+	if len(tail) > o.size {
+		tail = tail[len(tail)-o.size:]
+	}
+	o.task.setTail(tail)
+
+	return len(p), nil
+}
+
+func (o *execOutput) push(line string) {
+	line = execLine(line)
+	if line == "" {
+		return
+	}
+
+	if o.plain {
+		plainLine("  " + line)
+	}
+
+	o.tail = append(o.tail, line)
+	if len(o.tail) > o.size {
+		o.tail = append([]string(nil), o.tail[len(o.tail)-o.size:]...)
+	}
+}
+
+// flush pushes the last line, if the output did not end with a line break
+func (o *execOutput) flush() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.push(o.partial)
+	o.partial = ""
+}
+
+// execLine turns a line of output into plain text; of lines redrawn with \r only the last version is kept
+func execLine(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if i := strings.LastIndex(line, "\r"); i >= 0 {
+		line = line[i+1:]
+	}
+
+	line = StripANSI(line)
+	line = strings.ReplaceAll(line, "\t", "    ")
+
+	return strings.TrimRightFunc(line, func(r rune) bool { return r == ' ' })
+}
+
+// Exec runs cmd with a spinner and a rolling window of its latest output lines.
+// The window collapses once the command succeeds; if it fails, the complete output
+// and the exit code are printed. Stdout and Stderr of cmd are replaced to capture the output.
+// Ctrl+C interrupts the command once, like RunContext: on unix the command runs in its own
+// process group, unless its Stdin is the terminal, and the whole group is interrupted or killed.
+func Exec(ctx context.Context, title string, cmd *exec.Cmd, options ...ExecOption) error {
+	o := &execOptions{
+		tail: 5,
+	}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	task := newTask(title)
+
+	output := &execOutput{
+		size: max(o.tail, 0),
+		task: task,
+
+		plain: plainOutput(),
+	}
+
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	isolated := execIsolate(cmd)
+
+	err := spin(ctx, task, func(ctx context.Context, task *Task) error {
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			done <- cmd.Wait()
+		}()
+
+		defer output.flush()
+
+		select {
+		case err := <-done:
+			return err
+
+		case <-ctx.Done():
+			// Ask the command to stop, where interrupts are not supported it is killed
+			if err := execInterrupt(cmd, isolated); err != nil {
+				execKill(cmd, isolated)
+			}
+			return <-done
+		}
+	}, true)
+
+	title, _, _ = task.status()
+	screen := &frame{plain: plainOutput()}
+
+	if errors.Is(err, errCleanupTimeout) {
+		execKill(cmd, isolated)
+	}
+
+	if errors.Is(err, ErrUserAborted) {
+		screen.line(resultLine(title, ErrUserAborted, ""))
+		return ErrUserAborted
+	}
+
+	if err != nil {
+		status := err.Error()
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			status = "exit code " + strconv.Itoa(exitErr.ExitCode())
+		}
+
+		screen.line(themeError(currentSymbols.Failure) + " " + themeText(title) + themeError(" ("+status+")"))
+
+		output.mu.Lock()
+		log := output.buf.String()
+		output.mu.Unlock()
+
+		// In plain output, the lines were already written as they came
+		if log != "" && !output.plain {
+			if !strings.HasSuffix(log, "\n") {
+				log += "\n"
+			}
+			os.Stdout.WriteString(log)
+		}
+
+		return err
+	}
+
+	screen.line(resultLine(title, nil, ""))
+	return nil
+}
+
+func MustExec(ctx context.Context, title string, cmd *exec.Cmd, options ...ExecOption) error {
+	err := Exec(ctx, title, cmd, options...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return err
+}
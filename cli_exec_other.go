@@ -0,0 +1,23 @@
+//go:build !unix
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+)
+
+// execIsolate leaves the command as it is, there are no process groups to separate it
+func execIsolate(cmd *exec.Cmd) bool {
+	return false
+}
+
+// execInterrupt asks the command to stop
+func execInterrupt(cmd *exec.Cmd, isolated bool) error {
+	return cmd.Process.Signal(os.Interrupt)
+}
+
+// execKill stops the command
+func execKill(cmd *exec.Cmd, isolated bool) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,45 @@
+//go:build unix
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// execIsolate starts the command in its own process group, so Ctrl+C in the terminal
+// does not reach it directly and Exec sends the only interrupt. A command reading from
+// the terminal stays in the foreground group, as it would be stopped otherwise.
+// It reports whether the command was isolated.
+func execIsolate(cmd *exec.Cmd) bool {
+	if f, ok := cmd.Stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return false
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+
+	return true
+}
+
+// execInterrupt asks the command, and its process group if isolated, to stop
+func execInterrupt(cmd *exec.Cmd, isolated bool) error {
+	return execSignal(cmd, isolated, syscall.SIGINT)
+}
+
+// execKill stops the command, and everything in its process group if isolated
+func execKill(cmd *exec.Cmd, isolated bool) error {
+	return execSignal(cmd, isolated, syscall.SIGKILL)
+}
+
+func execSignal(cmd *exec.Cmd, isolated bool, sig syscall.Signal) error {
+	if isolated {
+		return syscall.Kill(-cmd.Process.Pid, sig)
+	}
+	return cmd.Process.Signal(sig)
+}
@@ -1,12 +1,16 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -15,6 +19,17 @@ type fileEntry struct {
 	name  string
 	path  string
 	isDir bool
+
+	size    int64
+	modTime time.Time
+	mode    fs.FileMode
+
+	// link is the target of a symbolic link
+	link string
+
+	// err is set if the entry could not be inspected, broken if it is a link to a missing target
+	err    error
+	broken bool
 }
 
 // isRootDir checks if a path is a root directory (cross-platform)
@@ -33,336 +48,1187 @@ func isRootDir(path string) bool {
 	return path == "/"
 }
 
-func File(label string, types []string) (string, error) {
-	var result string
+// FileOption configures the File, Files and Directory prompts
+type FileOption func(*fileOptions)
 
-	err := withRawMode(func() error {
-		// Start in current directory
-		currentDir, err := os.Getwd()
-		if err != nil {
-			currentDir = "."
+type fileOptions struct {
+	createDir bool
+	writable  bool
+
+	hidden  bool
+	sort    FileSort
+	columns []FileColumn
+
+	startDir string
+
+	patterns  []string
+	gitIgnore bool
+	predicate func(fs.DirEntry) bool
+
+	preview bool
+
+	bookmarks []string
+	recent    bool
+
+	skipBroken bool
+}
+
+// FileSort is the order of entries in the file picker
+type FileSort int
+
+const (
+	FileSortName FileSort = iota
+	FileSortSize
+	FileSortModified
+	FileSortExtension
+)
+
+func (s FileSort) String() string {
+	switch s {
+	case FileSortSize:
+		return "size"
+	case FileSortModified:
+		return "modified"
+	case FileSortExtension:
+		return "extension"
+	}
+	return "name"
+}
+
+// FileColumn is a metadata column shown next to each entry
+type FileColumn int
+
+const (
+	FileColumnSize FileColumn = iota
+	FileColumnModified
+	FileColumnMode
+)
+
+// FileStartDir sets the directory the picker opens in, instead of the working directory
+func FileStartDir(dir string) FileOption {
+	return func(o *fileOptions) {
+		o.startDir = dir
+	}
+}
+
+// FilePatterns only shows files matching one of the glob patterns, e.g. "*.tar.gz" or "Dockerfile*".
+// Patterns starting with ! exclude matching files, e.g. "!*.bak". Matching ignores case.
+func FilePatterns(patterns ...string) FileOption {
+	return func(o *fileOptions) {
+		o.patterns = append(o.patterns, patterns...)
+	}
+}
+
+// FileGitIgnore hides files and directories excluded by the .gitignore files of the repository
+func FileGitIgnore() FileOption {
+	return func(o *fileOptions) {
+		o.gitIgnore = true
+	}
+}
+
+// FileFilter only shows files for which fn returns true; directories are always shown
+func FileFilter(fn func(fs.DirEntry) bool) FileOption {
+	return func(o *fileOptions) {
+		o.predicate = fn
+	}
+}
+
+// FilePreview shows the content of the highlighted entry next to or below the list; Ctrl+P toggles it
+func FilePreview() FileOption {
+	return func(o *fileOptions) {
+		o.preview = true
+	}
+}
+
+// FileShowHidden shows dotfiles from the start; Ctrl+T toggles them
+func FileShowHidden() FileOption {
+	return func(o *fileOptions) {
+		o.hidden = true
+	}
+}
+
+// FileSortBy sets the initial sort order; Ctrl+O cycles through the orders
+func FileSortBy(sort FileSort) FileOption {
+	return func(o *fileOptions) {
+		o.sort = sort
+	}
+}
+
+// FileColumns shows size, modification time or permission columns
+func FileColumns(columns ...FileColumn) FileOption {
+	return func(o *fileOptions) {
+		o.columns = columns
+	}
+}
+
+// FileCreateDir allows creating a new folder in the current directory with Ctrl+N
+func FileCreateDir() FileOption {
+	return func(o *fileOptions) {
+		o.createDir = true
+	}
+}
+
+// FileWritable only accepts a directory the user can create files in
+func FileWritable() FileOption {
+	return func(o *fileOptions) {
+		o.writable = true
+	}
+}
+
+// fileMode selects what the picker returns
+type fileMode int
+
+const (
+	fileModeSingle fileMode = iota
+	fileModeMulti
+	fileModeDirectory
+	fileModeSave
+)
+
+// filePicker is the interactive file browser behind File, Files and Directory
+type filePicker struct {
+	label   string
+	types   []string
+	mode    fileMode
+	options fileOptions
+
+	dir      string
+	entries  []fileEntry
+	filtered []fileEntry
+
+	selectedIdx  int
+	scrollOffset int
+	maxVisible   int
+	filter       string
+
+	// marked holds the files chosen in multi mode, in the order they were chosen
+	marked []string
+
+	// creating is set while the name of a new folder is entered
+	creating bool
+	newName  string
+
+	// nameInput is the file name entered in save mode, listFocus is set while browsing the list
+	nameInput string
+	listFocus bool
+
+	// pathMode is set while a path is typed instead of a filter;
+	// relative paths are resolved against pathBase
+	pathMode  bool
+	pathInput string
+	pathBase  string
+
+	// message is an error shown until the next key press
+	message string
+
+	// loader reads the current directory in the background, dirErr is set if that failed;
+	// keep is the path to highlight once it was read
+	loader *dirLoader
+	dirErr error
+	keep   string
+
+	// refresh asks the running prompt to redraw
+	refresh func()
+
+	// access caches whether entries can be opened
+	access map[string]bool
+
+	// ignore holds the .gitignore rules of the repository being browsed
+	ignore *gitIgnore
+
+	// jumping is set while the quick-jump list is shown
+	jumping  bool
+	jumpList []fileJump
+	jumpIdx  int
+
+	// preview caches the preview of the highlighted entry
+	previewPath  string
+	previewSize  int
+	previewLines []string
+
+	files  fileSystem
+	screen *frame
+}
+
+func newFilePicker(files fileSystem, label string, types []string, mode fileMode, options []FileOption) *filePicker {
+	p := &filePicker{
+		label: label,
+		types: types,
+		mode:  mode,
+
+		maxVisible: 12,
+
+		files:  files,
+		screen: &frame{},
+	}
+
+	for _, option := range options {
+		option(&p.options)
+	}
+
+	// Start in the configured or current directory
+	dir, err := files.Start(p.options.startDir)
+	if err != nil {
+		dir = "."
+	}
+	p.dir = dir
+
+	return p
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return home + path[1:]
+}
+
+// isPathInput reports whether a filter looks like the start of a path
+func isPathInput(filter string) bool {
+	for _, prefix := range []string{"/", "~", "./", "../"} {
+		if strings.HasPrefix(filter, prefix) {
+			return true
 		}
+	}
+	return false
+}
 
-		selectedIdx := 0
-		scrollOffset := 0
-		maxVisible := 12
-		filter := ""
-		lastLineCount := 0
+// resolvePath turns typed input into a path of the browsed file system
+func (p *filePicker) resolvePath(input string) string {
+	return p.files.Resolve(p.pathBase, input)
+}
 
-		// Hide cursor during selection
-		fmt.Print(escHideCursor)
-		defer fmt.Print(escShowCursor)
+// splitPathInput splits typed input into its directory part, including the trailing slash, and the name being typed
+func splitPathInput(input string) (string, string) {
+	if input == "~" {
+		return "~/", ""
+	}
 
-		// Helper to navigate to a new directory
-		navigateToDir := func(dir string) {
-			currentDir = dir
-			filter = ""
-			selectedIdx = 0
-			scrollOffset = 0
-		}
-
-		// Read directory contents
-		readDir := func(dir string) []fileEntry {
-			entries := []fileEntry{}
-
-			// Add parent directory option if not at root
-			if !isRootDir(dir) {
-				entries = append(entries, fileEntry{
-					name:  "..",
-					path:  filepath.Dir(dir),
-					isDir: true,
-				})
-			}
+	i := strings.LastIndex(input, "/")
+	return input[:i+1], input[i+1:]
+}
+
+// updatePath jumps to the directory of the typed path and filters by the name being typed
+func (p *filePicker) updatePath() {
+	dirPart, name := splitPathInput(p.pathInput)
+
+	if dir := p.resolvePath(dirPart); dir != p.dir {
+		if info, err := p.files.Stat(dir); err == nil && info.IsDir() {
+			p.selectedIdx = 0
+			p.scrollOffset = 0
+			p.load(dir, "")
+		}
+	}
+
+	p.filter = name
+	p.filterEntries()
+}
+
+// completePath extends the typed path by the longest prefix shared by all matching entries
+func (p *filePicker) completePath() {
+	dirPart, name := splitPathInput(p.pathInput)
+
+	var matches []fileEntry
+	for _, e := range p.entries {
+		if e.name != ".." && strings.HasPrefix(strings.ToLower(e.name), strings.ToLower(name)) {
+			matches = append(matches, e)
+		}
+	}
+
+	if len(matches) == 0 {
+		return
+	}
 
-			files, err := os.ReadDir(dir)
+	common := matches[0].name
+	for _, m := range matches[1:] {
+		common = commonPrefixFold(common, m.name)
+	}
+
+	if utf8.RuneCountInString(common) < utf8.RuneCountInString(name) {
+		return
+	}
+
+	p.pathInput = dirPart + common
+	if len(matches) == 1 && matches[0].isDir {
+		p.pathInput += "/"
+	}
+
+	p.updatePath()
+}
+
+// commonPrefixFold returns the longest prefix of a that b starts with, ignoring case.
+// It is cut between characters, never within one.
+func commonPrefixFold(a, b string) string {
+	n := 0
+	for n < len(a) && len(b) > 0 {
+		ra, sa := utf8.DecodeRuneInString(a[n:])
+		rb, sb := utf8.DecodeRuneInString(b)
+
+		// Invalid bytes only match themselves
+		same := a[n:n+sa] == b[:sb] || ra != utf8.RuneError && strings.EqualFold(string(ra), string(rb))
+		if !same {
+			break
+		}
+		n += sa
+		b = b[sb:]
+	}
+	return a[:n]
+}
+
+// exitPathMode returns to filtering
+func (p *filePicker) exitPathMode() {
+	p.pathMode = false
+	p.pathInput = ""
+	p.filter = ""
+	p.filterEntries()
+}
+
+// handlePathKey handles a key press in path mode and returns the chosen paths, if any
+func (p *filePicker) handlePathKey(key int, char rune) []string {
+	switch key {
+	case keyTab:
+		p.completePath()
+
+	case keyEnter:
+		path := p.resolvePath(p.pathInput)
+
+		info, err := p.files.Stat(path)
+		if err != nil {
+			// Fall back to the highlighted entry, e.g. after typing part of a name
+			entry, ok := p.current()
+			if !ok {
+				p.message = "no such file or directory"
+				return nil
+			}
+			path = entry.path
+			info, err = p.files.Stat(path)
 			if err != nil {
-				return entries
+				p.message = err.Error()
+				return nil
 			}
+		}
 
-			// Separate directories and files for sorting
-			var dirs, regularFiles []fileEntry
-			for _, f := range files {
-				// Skip hidden files
-				if strings.HasPrefix(f.Name(), ".") {
-					continue
-				}
+		if info.IsDir() {
+			p.exitPathMode()
+			p.navigate(path)
+			return nil
+		}
 
-				fullPath := filepath.Join(dir, f.Name())
-				entry := fileEntry{
-					name:  f.Name(),
-					path:  fullPath,
-					isDir: f.IsDir(),
-				}
+		if p.mode == fileModeDirectory {
+			p.message = "not a directory"
+			return nil
+		}
 
-				if f.IsDir() {
-					dirs = append(dirs, entry)
-				} else {
-					// Filter by extension if types specified
-					if len(types) > 0 {
-						ext := strings.ToLower(filepath.Ext(f.Name()))
-						match := false
-						for _, t := range types {
-							if ext == strings.ToLower(t) {
-								match = true
-								break
-							}
-						}
-						if !match {
-							continue
-						}
-					}
-					regularFiles = append(regularFiles, entry)
-				}
-			}
+		if p.mode == fileModeMulti {
+			p.toggle(path)
+			p.exitPathMode()
+			return nil
+		}
 
-			// Sort directories and files alphabetically
-			sort.Slice(dirs, func(i, j int) bool {
-				return strings.ToLower(dirs[i].name) < strings.ToLower(dirs[j].name)
-			})
-			sort.Slice(regularFiles, func(i, j int) bool {
-				return strings.ToLower(regularFiles[i].name) < strings.ToLower(regularFiles[j].name)
-			})
+		return []string{path}
 
-			entries = append(entries, dirs...)
-			entries = append(entries, regularFiles...)
-			return entries
+	case keyUp:
+		if p.selectedIdx > 0 {
+			p.selectedIdx--
 		}
 
-		allEntries := readDir(currentDir)
-		filteredEntries := allEntries
+	case keyDown:
+		if len(p.filtered) > 0 && p.selectedIdx < len(p.filtered)-1 {
+			p.selectedIdx++
+		}
 
-		// Filter entries by search term
-		filterEntries := func() {
-			if filter == "" {
-				filteredEntries = allEntries
-			} else {
-				filteredEntries = []fileEntry{}
-				lowerFilter := strings.ToLower(filter)
-				for _, e := range allEntries {
-					if strings.Contains(strings.ToLower(e.name), lowerFilter) {
-						filteredEntries = append(filteredEntries, e)
-					}
-				}
+	case keyEscape:
+		p.exitPathMode()
+
+	case keyBackspace:
+		p.pathInput = trimLastGrapheme(p.pathInput)
+		if p.pathInput == "" {
+			p.exitPathMode()
+		} else {
+			p.updatePath()
+		}
+
+	case keyCtrlU:
+		p.exitPathMode()
+
+	default:
+		if char != 0 && char >= 32 {
+			p.pathInput += string(char)
+			p.updatePath()
+		}
+	}
+
+	return nil
+}
+
+// checkWritable returns an error if no files can be created in dir
+func checkWritable(dir string) error {
+	if !canWrite(dir) {
+		return errors.New("directory is not writable")
+	}
+
+	return nil
+}
+
+// matchFile reports whether a file passes the extension, pattern and predicate filters
+func (p *filePicker) matchFile(f fs.DirEntry) bool {
+	name := strings.ToLower(f.Name())
+
+	var include, exclude []string
+	for _, t := range p.types {
+		include = append(include, "*"+strings.ToLower(t))
+	}
+	for _, pattern := range p.options.patterns {
+		pattern = strings.ToLower(pattern)
+
+		if negated, ok := strings.CutPrefix(pattern, "!"); ok {
+			exclude = append(exclude, negated)
+		} else {
+			include = append(include, pattern)
+		}
+	}
+
+	for _, pattern := range exclude {
+		if matchGlob(pattern, name) {
+			return false
+		}
+	}
+
+	if len(include) > 0 {
+		matched := false
+		for _, pattern := range include {
+			if matchGlob(pattern, name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if p.options.predicate != nil {
+		return p.options.predicate(f)
+	}
+
+	return true
+}
+
+// sortEntries sorts entries in place, directories first; sizes and times are ordered largest and newest first
+func sortEntries(entries []fileEntry, order FileSort) {
+	byName := func(a, b fileEntry) bool {
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+
+		if a.isDir != b.isDir {
+			return a.isDir
+		}
+
+		switch order {
+		case FileSortSize:
+			if a.size != b.size {
+				return a.size > b.size
 			}
-			if selectedIdx >= len(filteredEntries) {
-				selectedIdx = len(filteredEntries) - 1
+		case FileSortModified:
+			if !a.modTime.Equal(b.modTime) {
+				return a.modTime.After(b.modTime)
 			}
-			if selectedIdx < 0 {
-				selectedIdx = 0
+		case FileSortExtension:
+			extA, extB := strings.ToLower(filepath.Ext(a.name)), strings.ToLower(filepath.Ext(b.name))
+			if extA != extB {
+				return extA < extB
 			}
 		}
 
-		clearPrevious := func() {
-			for i := 0; i < lastLineCount; i++ {
-				fmt.Print("\033[A")
-				fmt.Print("\r\033[K")
+		return byName(a, b)
+	})
+}
+
+// formatBytes formats a byte count for humans, e.g. "4.2 KB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatModTime formats a modification time like ls does
+func formatModTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if time.Since(t) > 180*24*time.Hour || t.After(time.Now()) {
+		return t.Format("Jan _2  2006")
+	}
+	return t.Format("Jan _2 15:04")
+}
+
+// columns renders the metadata columns of an entry
+func (p *filePicker) columns(entry fileEntry) string {
+	if len(p.options.columns) == 0 || entry.name == ".." {
+		return strings.Repeat(" ", p.columnsWidth())
+	}
+
+	var sb strings.Builder
+	for _, c := range p.options.columns {
+		switch c {
+		case FileColumnMode:
+			sb.WriteString(fmt.Sprintf("%-10s  ", entry.mode.String()))
+		case FileColumnSize:
+			size := ""
+			if !entry.isDir {
+				size = formatBytes(entry.size)
 			}
+			sb.WriteString(fmt.Sprintf("%8s  ", size))
+		case FileColumnModified:
+			sb.WriteString(fmt.Sprintf("%-12s  ", formatModTime(entry.modTime)))
+		}
+	}
+	return sb.String()
+}
+
+// columnsWidth returns the width of the rendered metadata columns
+func (p *filePicker) columnsWidth() int {
+	width := 0
+	for _, c := range p.options.columns {
+		switch c {
+		case FileColumnMode:
+			width += 12
+		case FileColumnSize:
+			width += 10
+		case FileColumnModified:
+			width += 14
 		}
+	}
+	return width
+}
 
-		redraw := func() {
-			lineCount := 0
+// reload reads the current directory again, keeping the filter and highlighted entry
+func (p *filePicker) reload() {
+	var path string
+	if entry, ok := p.current(); ok {
+		path = entry.path
+	}
 
-			// Print label
-			prompt := themeAccent(bold(label))
-			if len(types) > 0 {
-				prompt += " " + themeSubtle("("+strings.Join(types, ", ")+")")
-			}
-			fmt.Print("\r\033[K" + prompt + "\r\n")
-			lineCount++
-
-			// Print current path
-			displayPath := currentDir
-			home, _ := os.UserHomeDir()
-			if home != "" && strings.HasPrefix(displayPath, home) {
-				displayPath = "~" + displayPath[len(home):]
-			}
-			fmt.Print("\r\033[K" + themeMuted("▸ ") + themeText(displayPath) + "\r\n")
-			lineCount++
+	p.load(p.dir, path)
+}
+
+// navigate switches to a new directory
+func (p *filePicker) navigate(dir string) {
+	p.filter = ""
+	p.selectedIdx = 0
+	p.scrollOffset = 0
 
-			// Print filter line if active
-			if filter != "" {
-				fmt.Print("\r\033[K" + themeMuted("/ ") + themeText(filter) + "\r\n")
-				lineCount++
+	p.load(dir, "")
+}
+
+// filterEntries filters entries by search term
+func (p *filePicker) filterEntries() {
+	if p.filter == "" {
+		p.filtered = p.entries
+	} else {
+		p.filtered = []fileEntry{}
+		lowerFilter := strings.ToLower(p.filter)
+		for _, e := range p.entries {
+			if strings.Contains(strings.ToLower(e.name), lowerFilter) {
+				p.filtered = append(p.filtered, e)
 			}
+		}
+	}
+	if p.selectedIdx >= len(p.filtered) {
+		p.selectedIdx = len(p.filtered) - 1
+	}
+	if p.selectedIdx < 0 {
+		p.selectedIdx = 0
+	}
+}
 
-			// Handle empty directory
-			if len(filteredEntries) == 0 {
-				fmt.Print("\r\033[K" + themeMuted("  (empty)") + "\r\n")
-				lineCount++
-			} else {
-				// Adjust scroll offset
-				if selectedIdx < scrollOffset {
-					scrollOffset = selectedIdx
-				}
-				if selectedIdx >= scrollOffset+maxVisible {
-					scrollOffset = selectedIdx - maxVisible + 1
-				}
+// current returns the highlighted entry
+func (p *filePicker) current() (fileEntry, bool) {
+	if len(p.filtered) == 0 {
+		return fileEntry{}, false
+	}
+	return p.filtered[p.selectedIdx], true
+}
 
-				// Print entries
-				visibleEnd := scrollOffset + maxVisible
-				if visibleEnd > len(filteredEntries) {
-					visibleEnd = len(filteredEntries)
-				}
+func (p *filePicker) isMarked(path string) bool {
+	for _, m := range p.marked {
+		if m == path {
+			return true
+		}
+	}
+	return false
+}
 
-				// Show scroll indicator at top
-				if scrollOffset > 0 {
-					fmt.Print("\r\033[K" + themeMuted("  ↑ more items above") + "\r\n")
-					lineCount++
-				}
+// toggle adds or removes a file from the multi mode selection
+func (p *filePicker) toggle(path string) {
+	for i, m := range p.marked {
+		if m == path {
+			p.marked = append(p.marked[:i], p.marked[i+1:]...)
+			return
+		}
+	}
+	p.marked = append(p.marked, path)
+}
 
-				for i := scrollOffset; i < visibleEnd; i++ {
-					entry := filteredEntries[i]
-					fmt.Print("\r\033[K")
+// updateGitIgnore switches the .gitignore rules when entering another repository
+func (p *filePicker) updateGitIgnore(dir string) {
+	// Only the local file system has repositories
+	if _, ok := p.files.(osFileSystem); !ok || !p.options.gitIgnore {
+		return
+	}
 
-					prefix := "  "
-					if i == selectedIdx {
-						prefix = themeSuccess("> ")
-					}
+	root := findGitRoot(dir)
 
-					icon := "  "
-					if entry.isDir {
-						icon = "▸ "
-					}
+	if root == "" {
+		p.ignore = nil
+	} else if p.ignore == nil || p.ignore.root != root {
+		p.ignore = newGitIgnore(root)
+	}
+}
 
-					name := entry.name
-					if entry.isDir && entry.name != ".." {
-						name += "/"
-					}
+func (p *filePicker) prompt() string {
+	prompt := themeAccent(bold(p.label))
 
-					if i == selectedIdx {
-						fmt.Print(prefix + icon + themeSuccess(name))
-					} else {
-						if entry.isDir {
-							fmt.Print(prefix + icon + themeAccent(name))
-						} else {
-							fmt.Print(prefix + icon + themeText(name))
-						}
-					}
-					fmt.Print("\r\n")
-					lineCount++
+	// Summarize the active filters
+	var filters []string
+	filters = append(filters, p.types...)
+	filters = append(filters, p.options.patterns...)
+	if p.options.gitIgnore {
+		filters = append(filters, "gitignore")
+	}
+	if p.options.predicate != nil {
+		filters = append(filters, "custom filter")
+	}
+
+	if len(filters) > 0 {
+		prompt += " " + themeSubtle("("+strings.Join(filters, ", ")+")")
+	}
+	return prompt
+}
+
+func (p *filePicker) help() string {
+	if p.creating {
+		return "Enter create • Esc cancel"
+	}
+
+	if p.jumping {
+		return "↑/↓ navigate • Enter go • Esc close"
+	}
+
+	if p.pathMode {
+		return "Tab complete • Enter open • ↑/↓ navigate • Esc cancel"
+	}
+
+	switch p.mode {
+	case fileModeMulti:
+		return "↑/↓ navigate • Space toggle • Enter open dir or confirm • ← parent • Type to filter • Esc clear • Ctrl+T hidden • Ctrl+O sort • Ctrl+P preview • Home jump"
+
+	case fileModeDirectory:
+		help := "↑/↓ navigate • Ctrl+S select • Enter/→ enter dir • ← parent • Type to filter • Esc clear • Ctrl+T hidden • Ctrl+O sort • Ctrl+P preview • Home jump"
+		if p.options.createDir {
+			help += " • Ctrl+N new folder"
+		}
+		return help
+
+	case fileModeSave:
+		if p.listFocus {
+			return "↑/↓ navigate • Enter open dir or take name • ← parent • → enter dir • Tab edit name • Ctrl+T hidden • Ctrl+O sort"
+		}
+		return "Type file name • Enter save • ↑/↓/Tab browse • ← parent • Ctrl+T hidden • Ctrl+O sort"
+	}
+
+	return "↑/↓ navigate • Enter select • ← parent • → enter dir • Type to filter • Esc clear • Ctrl+T hidden • Ctrl+O sort • Ctrl+P preview • Home jump"
+}
+
+// chosenDir returns the highlighted directory, or the current one if ".." is highlighted
+func (p *filePicker) chosenDir() string {
+	if entry, ok := p.current(); ok && entry.isDir && entry.name != ".." {
+		return entry.path
+	}
+	return p.dir
+}
+
+// createDir creates the folder entered by the user and opens it
+func (p *filePicker) createDir() {
+	name := strings.TrimSpace(p.newName)
+	if name == "" {
+		return
+	}
+
+	path := filepath.Join(p.dir, name)
+	if err := os.Mkdir(path, 0o755); err != nil {
+		p.message = err.Error()
+		return
+	}
+
+	p.creating = false
+	p.newName = ""
+	p.navigate(path)
+}
+
+// finish prints the final selection in place of the picker
+func (p *filePicker) finish(paths []string) {
+	if p.mode == fileModeDirectory {
+		p.rememberDir(paths[0])
+	} else {
+		p.rememberDir(p.files.Dir(paths[0]))
+	}
+
+	p.screen.clear()
+	p.screen.line(p.prompt())
+	for _, path := range paths {
+		p.screen.line(themeSuccess("> ") + themeText(path))
+	}
+}
+
+// entryLines renders the visible entries; they are printed by renderEntries, so the preview can be placed next to them
+func (p *filePicker) entryLines() []string {
+	var lines []string
+
+	// Show why the directory could not be read in place of its entries
+	if p.dirErr != nil {
+		lines = append(lines, themeError("  ✗ "+describeError(p.dirErr)))
+	}
+
+	// Handle empty directory
+	if len(p.filtered) == 0 {
+		if _, loading := p.loadingCount(); loading {
+			lines = append(lines, themeMuted("  loading…"))
+		} else if p.dirErr == nil {
+			lines = append(lines, themeMuted("  (empty)"))
+		}
+	} else {
+		// Adjust scroll offset
+		if p.selectedIdx < p.scrollOffset {
+			p.scrollOffset = p.selectedIdx
+		}
+		if p.selectedIdx >= p.scrollOffset+p.maxVisible {
+			p.scrollOffset = p.selectedIdx - p.maxVisible + 1
+		}
+
+		// Print entries
+		visibleEnd := p.scrollOffset + p.maxVisible
+		if visibleEnd > len(p.filtered) {
+			visibleEnd = len(p.filtered)
+		}
+
+		// Show scroll indicator at top
+		if p.scrollOffset > 0 {
+			lines = append(lines, themeMuted("  ↑ more items above"))
+		}
+
+		for i := p.scrollOffset; i < visibleEnd; i++ {
+			entry := p.filtered[i]
+
+			// In save mode the highlight is only shown while browsing the list
+			highlighted := i == p.selectedIdx && (p.mode != fileModeSave || p.listFocus)
+
+			prefix := "  "
+			if highlighted {
+				prefix = themeSuccess("> ")
+			}
+
+			icon := "  "
+			if entry.isDir {
+				icon = "▸ "
+			} else if p.mode == fileModeMulti {
+				icon = themeMuted("○ ")
+				if p.isMarked(entry.path) {
+					icon = themeSuccess("● ")
 				}
+			}
+
+			name := entry.name
+			if entry.isDir && entry.name != ".." {
+				name += "/"
+			}
+
+			columns := themeMuted(p.columns(entry))
 
-				// Show scroll indicator at bottom
-				if visibleEnd < len(filteredEntries) {
-					fmt.Print("\r\033[K" + themeMuted("  ↓ more items below") + "\r\n")
-					lineCount++
+			link := ""
+			if entry.link != "" {
+				link = themeMuted(" → " + entry.link)
+			}
+			if entry.broken {
+				link += themeError(" (broken)")
+			}
+
+			// Entries that cannot be opened are dimmed
+			if entry.name != ".." && !p.accessible(entry) {
+				lines = append(lines, prefix+columns+icon+themeMuted(name)+link+themeError(" (no access)"))
+				continue
+			}
+
+			if highlighted {
+				lines = append(lines, prefix+columns+icon+themeSuccess(name)+link)
+			} else {
+				if entry.isDir {
+					lines = append(lines, prefix+columns+icon+themeAccent(name)+link)
+				} else {
+					lines = append(lines, prefix+columns+icon+themeText(name)+link)
 				}
 			}
+		}
+
+		// Show scroll indicator at bottom
+		if visibleEnd < len(p.filtered) {
+			lines = append(lines, themeMuted("  ↓ more items below"))
+		}
+	}
+
+	return lines
+}
+
+func (p *filePicker) redraw() {
+	screen := p.screen
 
-			// Print help
-			fmt.Print("\r\033[K" + themeMuted("↑/↓ navigate • Enter select • ← parent • → enter dir • Type to filter • Esc clear") + "\r\n")
-			lineCount++
+	// Print label
+	screen.line(p.prompt())
 
-			lastLineCount = lineCount
+	// Print current path
+	displayPath := p.files.Display(p.dir)
+	status := ""
+	if p.options.sort != FileSortName {
+		status += " · sorted by " + p.options.sort.String()
+	}
+	if p.options.hidden {
+		status += " · hidden shown"
+	}
+	if n, loading := p.loadingCount(); loading {
+		status += " · loading " + strconv.Itoa(n) + " entries…"
+	}
+	screen.line(themeMuted("▸ ") + themeText(displayPath) + themeMuted(status))
+
+	// Print path or filter line if active
+	if p.pathMode {
+		screen.line(themeAccent("→ ") + themeText(p.pathInput) + themeSubtle("█"))
+	} else if p.filter != "" {
+		screen.line(themeMuted("/ ") + themeText(p.filter))
+	}
+
+	// The lines below the entries are known first, so the preview can leave room for them
+	var footer []string
+
+	// Print file name input
+	if p.mode == fileModeSave {
+		cursor := themeSubtle("█")
+		if p.listFocus {
+			cursor = ""
 		}
+		footer = append(footer, themeAccent("Name: ")+themeText(p.nameInput)+cursor)
+	}
+
+	// Print new folder input
+	if p.creating {
+		footer = append(footer, themeAccent("+ ")+themeText(p.newName)+themeSubtle("█"))
+	}
+
+	// Print selection count
+	if p.mode == fileModeMulti && len(p.marked) > 0 {
+		footer = append(footer, themeSuccess("  "+strconv.Itoa(len(p.marked))+" selected"))
+	}
+
+	// Print error
+	if p.message != "" {
+		footer = append(footer, themeError("  "+p.message))
+	}
+
+	// Print help
+	footer = append(footer, themeMuted(p.help()))
+
+	// Print quick-jump list or entries
+	if p.jumping {
+		p.renderJumps()
+	} else {
+		p.renderEntries(p.entryLines(), len(footer))
+	}
+
+	for _, line := range footer {
+		screen.line(line)
+	}
+}
+
+// run shows the picker and returns the chosen paths
+func (p *filePicker) run() ([]string, error) {
+	var result []string
+
+	err := withRawMode(func() error {
+		keys := newKeyReader(os.Stdin)
+		defer keys.Close()
+
+		// Hide cursor during selection
+		fmt.Print(escHideCursor)
+		defer fmt.Print(escShowCursor)
+
+		// Entries read in the background are shown as they arrive
+		p.refresh = keys.Refresh
+		defer p.stopLoading()
+
+		p.navigate(p.dir)
 
 		// Initial draw
-		redraw()
+		p.redraw()
 
 		for {
-			key, char, err := readKey(os.Stdin)
+			key, char, err := keys.Read()
 			if err != nil {
 				return err
 			}
 
+			p.drain()
+
+			if key == keyResize || key == keyRefresh {
+				p.screen.clear()
+				p.redraw()
+				continue
+			}
+
+			p.message = ""
+
+			// Keys go to the folder name while creating a new folder
+			if p.creating {
+				switch key {
+				case keyCtrlC:
+					p.screen.clear()
+					return ErrUserAborted
+
+				case keyEnter:
+					p.createDir()
+
+				case keyEscape:
+					p.creating = false
+					p.newName = ""
+
+				case keyBackspace:
+					p.newName = trimLastGrapheme(p.newName)
+
+				default:
+					if char != 0 && char >= 32 {
+						p.newName += string(char)
+					}
+				}
+
+				p.screen.clear()
+				p.redraw()
+				continue
+			}
+
+			// Keys go to the quick-jump list while it is open
+			if p.jumping {
+				if key == keyCtrlC {
+					p.screen.clear()
+					return ErrUserAborted
+				}
+
+				p.handleJumpKey(key)
+
+				p.screen.clear()
+				p.redraw()
+				continue
+			}
+
+			// Keys go to the file name in save mode
+			if p.mode == fileModeSave {
+				if key == keyCtrlC {
+					p.screen.clear()
+					return ErrUserAborted
+				}
+
+				paths, err := p.handleSaveKey(key, char)
+				if err != nil {
+					return err
+				}
+
+				if result = paths; result != nil {
+					p.finish(result)
+					return nil
+				}
+
+				p.screen.clear()
+				p.redraw()
+				continue
+			}
+
+			// Keys go to the typed path in path mode
+			if p.pathMode {
+				if key == keyCtrlC {
+					p.screen.clear()
+					return ErrUserAborted
+				}
+
+				if result = p.handlePathKey(key, char); result != nil {
+					p.finish(result)
+					return nil
+				}
+
+				p.screen.clear()
+				p.redraw()
+				continue
+			}
+
 			switch key {
 			case keyCtrlC:
-				clearPrevious()
+				p.screen.clear()
 				return ErrUserAborted
 
 			case keyEnter:
-				if len(filteredEntries) > 0 {
-					entry := filteredEntries[selectedIdx]
-					if entry.isDir {
+				// A directory is always opened; on a file, Enter confirms the toggled files in multi mode
+				if entry, ok := p.current(); ok {
+					if entry.broken {
+						p.message = "link target does not exist"
+					} else if entry.isDir {
 						// Navigate into directory
-						navigateToDir(entry.path)
-						allEntries = readDir(currentDir)
-						filteredEntries = allEntries
+						p.navigate(entry.path)
+					} else if len(p.marked) > 0 {
+						result = p.marked
 					} else {
 						// Select file
-						result = entry.path
-						clearPrevious()
-						// Show final selection
-						prompt := themeAccent(bold(label))
-						if len(types) > 0 {
-							prompt += " " + themeSubtle("("+strings.Join(types, ", ")+")")
+						result = []string{entry.path}
+					}
+				} else if len(p.marked) > 0 {
+					result = p.marked
+				}
+
+				if result != nil {
+					p.finish(result)
+					return nil
+				}
+
+			case keyCtrlS:
+				if p.mode == fileModeDirectory {
+					dir := p.chosenDir()
+
+					if p.options.writable {
+						if err := checkWritable(dir); err != nil {
+							p.message = err.Error()
+							break
 						}
-						fmt.Print("\r\033[K" + prompt + "\r\n")
-						fmt.Print("\r\033[K" + themeSuccess("> ") + themeText(result) + "\r\n")
-						return nil
 					}
+
+					result = []string{dir}
+					p.finish(result)
+					return nil
+				}
+
+			case keyCtrlT:
+				p.options.hidden = !p.options.hidden
+				p.reload()
+
+			case keyCtrlO:
+				p.options.sort = (p.options.sort + 1) % (FileSortExtension + 1)
+				p.reload()
+
+			case keyCtrlP:
+				p.options.preview = !p.options.preview
+
+			case keyCtrlN:
+				if p.mode == fileModeDirectory && p.options.createDir {
+					p.creating = true
+				}
+
+			case keySpace:
+				if p.mode != fileModeMulti {
+					p.filter += " "
+					p.filterEntries()
+				} else if entry, ok := p.current(); ok && !entry.isDir {
+					p.toggle(entry.path)
 				}
 
 			case keyUp:
-				if selectedIdx > 0 {
-					selectedIdx--
+				if p.selectedIdx > 0 {
+					p.selectedIdx--
 				}
 
 			case keyDown:
-				if len(filteredEntries) > 0 && selectedIdx < len(filteredEntries)-1 {
-					selectedIdx++
+				if len(p.filtered) > 0 && p.selectedIdx < len(p.filtered)-1 {
+					p.selectedIdx++
 				}
 
 			case keyLeft:
 				// Go to parent directory
-				if !isRootDir(currentDir) {
-					navigateToDir(filepath.Dir(currentDir))
-					allEntries = readDir(currentDir)
-					filteredEntries = allEntries
+				if !p.files.IsRoot(p.dir) {
+					p.navigate(p.files.Dir(p.dir))
 				}
 
 			case keyRight:
 				// Enter directory if selected
-				if len(filteredEntries) > 0 {
-					entry := filteredEntries[selectedIdx]
-					if entry.isDir {
-						navigateToDir(entry.path)
-						allEntries = readDir(currentDir)
-						filteredEntries = allEntries
-					}
+				if entry, ok := p.current(); ok && entry.isDir {
+					p.navigate(entry.path)
 				}
 
 			case keyBackspace:
-				if len(filter) > 0 {
-					_, size := utf8.DecodeLastRuneInString(filter)
-					filter = filter[:len(filter)-size]
-					filterEntries()
+				if len(p.filter) > 0 {
+					p.filter = trimLastGrapheme(p.filter)
+					p.filterEntries()
 				}
 
 			case keyEscape:
-				filter = ""
-				filterEntries()
-
-			case keyHome:
-				// Go to home directory
-				home, err := os.UserHomeDir()
-				if err == nil {
-					navigateToDir(home)
-					allEntries = readDir(currentDir)
-					filteredEntries = allEntries
-				}
+				p.filter = ""
+				p.filterEntries()
+
+			case keyHome, keyCtrlG:
+				// Show home, bookmarks and recent directories
+				p.openJumps()
 
 			default:
 				if char != 0 && char >= 32 {
-					filter += string(char)
-					filterEntries()
+					p.filter += string(char)
+
+					// Typing /, ~, ./ or ../ switches to entering a path
+					if isPathInput(p.filter) {
+						p.pathMode = true
+						p.pathInput = p.filter
+						p.pathBase = p.dir
+						p.updatePath()
+					} else {
+						p.filterEntries()
+					}
 				}
 			}
 
-			clearPrevious()
-			redraw()
+			p.screen.clear()
+			p.redraw()
 		}
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	return result, nil
 }
 
-func MustFile(label string, types []string) string {
-	value, err := File(label, types)
+func File(label string, types []string, options ...FileOption) (string, error) {
+	paths, err := newFilePicker(osFileSystem{}, label, types, fileModeSingle, options).run()
+
+	if err != nil {
+		return "", err
+	}
+
+	return paths[0], nil
+}
+
+func MustFile(label string, types []string, options ...FileOption) string {
+	value, err := File(label, types, options...)
 
 	if err != nil {
 		Fatal(err)
@@ -370,3 +1236,79 @@ func MustFile(label string, types []string) string {
 
 	return value
 }
+
+// Files lets the user toggle multiple files with Space, across directories.
+// Enter on a directory opens it; on a file, it returns the toggled files, or the
+// highlighted file if none are toggled yet.
+func Files(label string, types []string, options ...FileOption) ([]string, error) {
+	return newFilePicker(osFileSystem{}, label, types, fileModeMulti, options).run()
+}
+
+func MustFiles(label string, types []string, options ...FileOption) []string {
+	values, err := Files(label, types, options...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return values
+}
+
+// Directory lets the user browse to a directory and choose it with Ctrl+S.
+// The highlighted directory is chosen, or the current one if ".." is highlighted.
+func Directory(label string, options ...FileOption) (string, error) {
+	paths, err := newFilePicker(osFileSystem{}, label, nil, fileModeDirectory, options).run()
+
+	if err != nil {
+		return "", err
+	}
+
+	return paths[0], nil
+}
+
+func MustDirectory(label string, options ...FileOption) string {
+	value, err := Directory(label, options...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return value
+}
+
+// FileFS lets the user pick a file from fsys, e.g. an embed.FS or a zip.Reader.
+// The returned path is relative to the root of fsys and can be passed to fsys.Open.
+func FileFS(fsys fs.FS, label string, types []string, options ...FileOption) (string, error) {
+	paths, err := newFilePicker(fsFileSystem{fsys}, label, types, fileModeSingle, options).run()
+
+	if err != nil {
+		return "", err
+	}
+
+	return paths[0], nil
+}
+
+func MustFileFS(fsys fs.FS, label string, types []string, options ...FileOption) string {
+	value, err := FileFS(fsys, label, types, options...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return value
+}
+
+// FilesFS is the multi-file variant of FileFS
+func FilesFS(fsys fs.FS, label string, types []string, options ...FileOption) ([]string, error) {
+	return newFilePicker(fsFileSystem{fsys}, label, types, fileModeMulti, options).run()
+}
+
+func MustFilesFS(fsys fs.FS, label string, types []string, options ...FileOption) []string {
+	values, err := FilesFS(fsys, label, types, options...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return values
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// fileSystem is the storage browsed by the file picker
+type fileSystem interface {
+	ReadDir(dir string) ([]fs.DirEntry, error)
+	Stat(name string) (fs.FileInfo, error)
+	ReadLink(name string) (string, error)
+	Open(name string) (fs.File, error)
+
+	Join(elem ...string) string
+	Dir(name string) string
+	IsRoot(name string) bool
+
+	// Start returns the directory to open the picker in, or an error if dir is not usable
+	Start(dir string) (string, error)
+
+	// Resolve turns a path typed by the user into a path of the file system, relative to base
+	Resolve(base, input string) string
+
+	// Display formats a path for the header of the picker
+	Display(name string) string
+}
+
+// osFileSystem browses the local file system
+type osFileSystem struct{}
+
+func (osFileSystem) ReadDir(dir string) ([]fs.DirEntry, error) {
+	return os.ReadDir(dir)
+}
+
+func (osFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFileSystem) ReadLink(name string) (string, error) {
+	return os.Readlink(name)
+}
+
+func (osFileSystem) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFileSystem) Join(elem ...string) string {
+	return filepath.Join(elem...)
+}
+
+func (osFileSystem) Dir(name string) string {
+	return filepath.Dir(name)
+}
+
+func (osFileSystem) IsRoot(name string) bool {
+	return isRootDir(name)
+}
+
+func (osFileSystem) Start(dir string) (string, error) {
+	if dir == "" {
+		return os.Getwd()
+	}
+	return filepath.Abs(expandHome(dir))
+}
+
+func (osFileSystem) Resolve(base, input string) string {
+	name := filepath.FromSlash(expandHome(input))
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(base, name)
+	}
+	return filepath.Clean(name)
+}
+
+func (osFileSystem) Display(name string) string {
+	home, _ := os.UserHomeDir()
+	if home != "" && strings.HasPrefix(name, home) {
+		return "~" + name[len(home):]
+	}
+	return name
+}
+
+// fsFileSystem browses an fs.FS, e.g. an embed.FS or a zip.Reader.
+// Paths are slash-separated and relative to the root of the FS, which is ".".
+type fsFileSystem struct {
+	fsys fs.FS
+}
+
+func (f fsFileSystem) ReadDir(dir string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(f.fsys, dir)
+}
+
+func (f fsFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(f.fsys, name)
+}
+
+func (f fsFileSystem) ReadLink(name string) (string, error) {
+	return fs.ReadLink(f.fsys, name)
+}
+
+func (f fsFileSystem) Open(name string) (fs.File, error) {
+	return f.fsys.Open(name)
+}
+
+func (fsFileSystem) Join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+func (fsFileSystem) Dir(name string) string {
+	return path.Dir(name)
+}
+
+func (fsFileSystem) IsRoot(name string) bool {
+	return name == "."
+}
+
+func (f fsFileSystem) Start(dir string) (string, error) {
+	// As in Resolve, both / and ~ refer to the root of the FS
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		dir = dir[1:]
+	}
+
+	dir = path.Clean(strings.TrimPrefix(dir, "/"))
+	if dir == "" || dir == "." {
+		return ".", nil
+	}
+	if !fs.ValidPath(dir) {
+		return "", &fs.PathError{Op: "open", Path: dir, Err: fs.ErrInvalid}
+	}
+	return dir, nil
+}
+
+func (fsFileSystem) Resolve(base, input string) string {
+	// Both / and ~ refer to the root of the FS
+	switch {
+	case strings.HasPrefix(input, "/"):
+		input = strings.TrimPrefix(input, "/")
+		base = "."
+	case input == "~" || strings.HasPrefix(input, "~/"):
+		input = strings.TrimPrefix(input[1:], "/")
+		base = "."
+	}
+
+	name := path.Join(base, input)
+
+	// Paths cannot leave the root of the FS
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return "."
+	}
+	return name
+}
+
+func (fsFileSystem) Display(name string) string {
+	if name == "." {
+		return "/"
+	}
+	return "/" + name
+}
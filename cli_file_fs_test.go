@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestFSFileSystemStart(t *testing.T) {
+	files := fsFileSystem{fstest.MapFS{}}
+
+	tests := []struct {
+		dir  string
+		want string
+		err  bool
+	}{
+		{dir: "", want: "."},
+		{dir: ".", want: "."},
+		{dir: "/", want: "."},
+		{dir: "~", want: "."},
+		{dir: "~/docs", want: "docs"},
+		{dir: "/docs/", want: "docs"},
+		{dir: "docs/../src", want: "src"},
+		{dir: "..", err: true},
+		{dir: "../etc", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := files.Start(tt.dir)
+
+		if tt.err {
+			if err == nil {
+				t.Errorf("Start(%q) = %q, want error", tt.dir, got)
+			}
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("Start(%q) = %q, %v, want %q", tt.dir, got, err, tt.want)
+		}
+	}
+}
+
+func TestFSFileSystemResolve(t *testing.T) {
+	files := fsFileSystem{fstest.MapFS{}}
+
+	tests := []struct {
+		base  string
+		input string
+		want  string
+	}{
+		{base: ".", input: "docs", want: "docs"},
+		{base: "docs", input: "readme.md", want: "docs/readme.md"},
+		{base: "docs", input: "..", want: "."},
+		{base: "docs", input: "../src/main.go", want: "src/main.go"},
+
+		// Paths cannot leave the root
+		{base: ".", input: "..", want: "."},
+		{base: "docs", input: "../..", want: "."},
+		{base: "docs", input: "../../etc/passwd", want: "."},
+
+		// / and ~ refer to the root
+		{base: "docs", input: "/", want: "."},
+		{base: "docs", input: "/src", want: "src"},
+		{base: "docs", input: "/../etc", want: "."},
+		{base: "docs", input: "~", want: "."},
+		{base: "docs", input: "~/src", want: "src"},
+		{base: "docs", input: "~src", want: "docs/~src"},
+	}
+
+	for _, tt := range tests {
+		if got := files.Resolve(tt.base, tt.input); got != tt.want {
+			t.Errorf("Resolve(%q, %q) = %q, want %q", tt.base, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFilePickerLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"b.txt":          {Data: []byte("b")},
+		"a.md":           {Data: []byte("a")},
+		".hidden":        {Data: []byte("h")},
+		"src/main.go":    {Data: []byte("package main")},
+		"docs/readme.md": {Data: []byte("# readme")},
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		types   []string
+		options []FileOption
+		want    []string
+	}{
+		{name: "root", dir: ".", want: []string{"docs", "src", "a.md", "b.txt"}},
+		{name: "subdirectory", dir: "docs", want: []string{"..", "readme.md"}},
+		{name: "hidden", dir: ".", options: []FileOption{FileShowHidden()}, want: []string{"docs", "src", ".hidden", "a.md", "b.txt"}},
+		{name: "types", dir: ".", types: []string{".md"}, want: []string{"docs", "src", "a.md"}},
+		{name: "missing", dir: "missing", want: []string{".."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newFilePicker(fsFileSystem{fsys}, "Pick", tt.types, fileModeSingle, tt.options)
+			p.load(tt.dir, "")
+
+			deadline := time.Now().Add(time.Second)
+			for p.loader != nil {
+				if time.Now().After(deadline) {
+					t.Fatal("directory did not finish loading")
+				}
+				time.Sleep(time.Millisecond)
+				p.drain()
+			}
+
+			var got []string
+			for _, e := range p.entries {
+				got = append(got, e.name)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("entries = %q, want %q", got, tt.want)
+			}
+
+			if tt.dir == "missing" && p.dirErr == nil {
+				t.Error("expected an error for a missing directory")
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxRecentDirs is the number of recent directories kept between runs
+const maxRecentDirs = 10
+
+// fileJump is an entry of the quick-jump list of the file picker
+type fileJump struct {
+	label string
+	path  string
+}
+
+// FileBookmarks adds directories to the quick-jump list, opened with Home or Ctrl+G
+func FileBookmarks(dirs ...string) FileOption {
+	return func(o *fileOptions) {
+		o.bookmarks = append(o.bookmarks, dirs...)
+	}
+}
+
+// FileRecent remembers the directories files were picked from between runs
+// and offers them in the quick-jump list
+func FileRecent() FileOption {
+	return func(o *fileOptions) {
+		o.recent = true
+	}
+}
+
+// recentDirsFile returns the file the recent directories of this program are stored in
+func recentDirsFile() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	return filepath.Join(dir, name, "recent-dirs"), nil
+}
+
+// loadRecentDirs returns the recent directories, most recent first
+func loadRecentDirs() []string {
+	path, err := recentDirsFile()
+	if err != nil {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var dirs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if dir := strings.TrimSpace(scanner.Text()); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// saveRecentDir moves dir to the top of the recent directories
+func saveRecentDir(dir string) error {
+	path, err := recentDirsFile()
+	if err != nil {
+		return err
+	}
+
+	dirs := []string{dir}
+	for _, d := range loadRecentDirs() {
+		if d != dir && len(dirs) < maxRecentDirs {
+			dirs = append(dirs, d)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(dirs, "\n")+"\n"), 0o644)
+}
+
+// jumps returns the quick-jump list: home, working directory, repository root, bookmarks and recent directories
+func (p *filePicker) jumps() []fileJump {
+	var result []fileJump
+	seen := map[string]bool{}
+
+	add := func(label, path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		if info, err := p.files.Stat(path); err != nil || !info.IsDir() {
+			return
+		}
+
+		seen[path] = true
+		result = append(result, fileJump{label, path})
+	}
+
+	if _, ok := p.files.(osFileSystem); ok {
+		home, _ := os.UserHomeDir()
+		add("Home", home)
+
+		cwd, _ := os.Getwd()
+		add("Working directory", cwd)
+
+		add("Repository root", findGitRoot(p.dir))
+	} else {
+		add("Root", ".")
+	}
+
+	for _, dir := range p.options.bookmarks {
+		if path, err := p.files.Start(dir); err == nil {
+			add("Bookmark", path)
+		}
+	}
+
+	if _, ok := p.files.(osFileSystem); ok && p.options.recent {
+		for _, dir := range loadRecentDirs() {
+			add("Recent", dir)
+		}
+	}
+
+	return result
+}
+
+// rememberDir records the directory of a picked path in the recent directories
+func (p *filePicker) rememberDir(dir string) {
+	if _, ok := p.files.(osFileSystem); !ok || !p.options.recent {
+		return
+	}
+
+	saveRecentDir(dir)
+}
+
+// openJumps shows the quick-jump list
+func (p *filePicker) openJumps() {
+	p.jumpList = p.jumps()
+	p.jumpIdx = 0
+	p.jumping = true
+}
+
+// renderJumps prints the quick-jump list in place of the entries
+func (p *filePicker) renderJumps() {
+	if len(p.jumpList) == 0 {
+		p.screen.line(themeMuted("  (no locations)"))
+		return
+	}
+
+	width := 0
+	for _, j := range p.jumpList {
+		width = max(width, VisibleWidth(j.label))
+	}
+
+	for i, j := range p.jumpList {
+		label := j.label + strings.Repeat(" ", width-VisibleWidth(j.label))
+		path := p.files.Display(j.path)
+
+		if i == p.jumpIdx {
+			p.screen.line(themeSuccess("> ") + themeSuccess(label) + "  " + themeSuccess(path))
+		} else {
+			p.screen.line("  " + themeMuted(label) + "  " + themeText(path))
+		}
+	}
+}
+
+// handleJumpKey handles a key press while the quick-jump list is open
+func (p *filePicker) handleJumpKey(key int) {
+	switch key {
+	case keyUp:
+		if p.jumpIdx > 0 {
+			p.jumpIdx--
+		}
+
+	case keyDown:
+		if p.jumpIdx < len(p.jumpList)-1 {
+			p.jumpIdx++
+		}
+
+	case keyEnter, keyRight:
+		p.jumping = false
+		if p.jumpIdx < len(p.jumpList) {
+			p.navigate(p.jumpList[p.jumpIdx].path)
+		}
+
+	case keyEscape, keyHome, keyCtrlG:
+		p.jumping = false
+	}
+}
@@ -0,0 +1,340 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dirLoader reads a directory in the background, so large directories don't block the picker
+type dirLoader struct {
+	mu      sync.Mutex
+	pending []fileEntry
+	count   int
+	err     error
+	done    bool
+
+	cancel   chan struct{}
+	finished chan struct{}
+}
+
+// take returns the entries read since the last call
+func (l *dirLoader) take() ([]fileEntry, error, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := l.pending
+	l.pending = nil
+
+	return entries, l.err, l.done
+}
+
+func (l *dirLoader) add(entries []fileEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending = append(l.pending, entries...)
+	l.count += len(entries)
+}
+
+func (l *dirLoader) finish(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.err = err
+	l.done = true
+	close(l.finished)
+}
+
+func (l *dirLoader) canceled() bool {
+	select {
+	case <-l.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// FileSkipBrokenLinks hides symbolic links whose target does not exist, instead of marking them
+func FileSkipBrokenLinks() FileOption {
+	return func(o *fileOptions) {
+		o.skipBroken = true
+	}
+}
+
+// describeError turns a file system error into a short message
+func describeError(err error) string {
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return "permission denied"
+	case errors.Is(err, fs.ErrNotExist):
+		return "no such file or directory"
+	}
+
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+
+	return err.Error()
+}
+
+// newEntry converts a directory entry, reporting false if it is filtered out
+func (p *filePicker) newEntry(dir string, f fs.DirEntry, hidden bool, ignore *gitIgnore) (fileEntry, bool) {
+	// Skip hidden files
+	if !hidden && strings.HasPrefix(f.Name(), ".") {
+		return fileEntry{}, false
+	}
+
+	entry := fileEntry{
+		name:  f.Name(),
+		path:  p.files.Join(dir, f.Name()),
+		isDir: f.IsDir(),
+	}
+
+	if ignore != nil && ignore.ignored(entry.path, entry.isDir) {
+		return fileEntry{}, false
+	}
+
+	if info, err := f.Info(); err == nil {
+		entry.size = info.Size()
+		entry.modTime = info.ModTime()
+		entry.mode = info.Mode()
+	} else {
+		entry.err = err
+	}
+
+	// Symbolic links show their target and can be entered if they point to a directory
+	if f.Type()&fs.ModeSymlink != 0 {
+		entry.link, _ = p.files.ReadLink(entry.path)
+
+		if info, err := p.files.Stat(entry.path); err == nil {
+			entry.isDir = info.IsDir()
+		} else if p.options.skipBroken {
+			return fileEntry{}, false
+		} else {
+			entry.broken = true
+		}
+	}
+
+	if !entry.isDir && (p.mode == fileModeDirectory || !p.matchFile(f)) {
+		return fileEntry{}, false
+	}
+
+	return entry, true
+}
+
+// load starts reading dir in the background. Entries are picked up by drain;
+// keep is the path to highlight once it was read, if any.
+func (p *filePicker) load(dir, keep string) {
+	p.stopLoading()
+	p.updateGitIgnore(dir)
+
+	p.dir = dir
+	p.dirErr = nil
+	p.keep = keep
+	p.access = nil
+	p.entries = []fileEntry{}
+
+	// Add parent directory option if not at root
+	if !p.files.IsRoot(dir) {
+		p.entries = append(p.entries, fileEntry{
+			name:  "..",
+			path:  p.files.Dir(dir),
+			isDir: true,
+		})
+	}
+	p.filterEntries()
+
+	l := &dirLoader{
+		cancel:   make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	p.loader = l
+
+	// Options may change while reading, so the goroutine works on a snapshot
+	hidden := p.options.hidden
+	ignore := p.ignore
+	refresh := p.refresh
+
+	notify := func() {
+		if refresh != nil {
+			refresh()
+		}
+	}
+
+	convert := func(files []fs.DirEntry) []fileEntry {
+		var entries []fileEntry
+		for _, f := range files {
+			if entry, ok := p.newEntry(dir, f, hidden, ignore); ok {
+				entries = append(entries, entry)
+			}
+		}
+		return entries
+	}
+
+	go func() {
+		defer notify()
+
+		f, err := p.files.Open(dir)
+		if err != nil {
+			l.finish(err)
+			return
+		}
+		defer f.Close()
+
+		// Read all at once if the file system cannot list a directory in chunks
+		rd, ok := f.(fs.ReadDirFile)
+		if !ok {
+			files, err := p.files.ReadDir(dir)
+			l.add(convert(files))
+			l.finish(err)
+			return
+		}
+
+		last := time.Now()
+
+		for !l.canceled() {
+			files, err := rd.ReadDir(256)
+			l.add(convert(files))
+
+			if err == io.EOF {
+				l.finish(nil)
+				return
+			}
+			if err != nil {
+				l.finish(err)
+				return
+			}
+
+			// Show progress without redrawing for every chunk
+			if time.Since(last) > 100*time.Millisecond {
+				last = time.Now()
+				notify()
+			}
+		}
+	}()
+
+	// Give small directories the chance to be shown complete right away
+	select {
+	case <-l.finished:
+	case <-time.After(20 * time.Millisecond):
+	}
+	p.drain()
+}
+
+// drain adds the entries read in the background so far
+func (p *filePicker) drain() {
+	l := p.loader
+	if l == nil {
+		return
+	}
+
+	entries, err, done := l.take()
+
+	if done {
+		p.loader = nil
+		p.dirErr = err
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+
+	keep := p.keep
+	if entry, ok := p.current(); ok && keep == "" && p.selectedIdx > 0 {
+		keep = entry.path
+	}
+
+	// Keep ".." in front and sort the rest
+	start := 0
+	if len(p.entries) > 0 && p.entries[0].name == ".." {
+		start = 1
+	}
+
+	p.entries = append(p.entries, entries...)
+	sortEntries(p.entries[start:], p.options.sort)
+
+	p.filterEntries()
+
+	if keep != "" {
+		for i, e := range p.filtered {
+			if e.path == keep {
+				p.selectedIdx = i
+				p.keep = ""
+				break
+			}
+		}
+	}
+}
+
+// stopLoading cancels reading the current directory
+func (p *filePicker) stopLoading() {
+	if p.loader != nil {
+		close(p.loader.cancel)
+		p.loader = nil
+	}
+}
+
+// loadingCount returns the number of entries read so far while loading
+func (p *filePicker) loadingCount() (int, bool) {
+	l := p.loader
+	if l == nil {
+		return 0, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.count, true
+}
+
+// readDirLimit reads up to n entries of a directory and reports whether there are more
+func readDirLimit(files fileSystem, dir string, n int) ([]fs.DirEntry, bool, error) {
+	f, err := files.Open(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	rd, ok := f.(fs.ReadDirFile)
+	if !ok {
+		entries, err := files.ReadDir(dir)
+		if len(entries) > n {
+			return entries[:n], true, err
+		}
+		return entries, false, err
+	}
+
+	entries, err := rd.ReadDir(n)
+	if err == io.EOF {
+		err = nil
+	}
+
+	return entries, len(entries) == n, err
+}
+
+// accessible reports whether an entry can be opened. Checks are cached and only done for the local file system.
+func (p *filePicker) accessible(entry fileEntry) bool {
+	if entry.err != nil {
+		return false
+	}
+
+	if _, ok := p.files.(osFileSystem); !ok || entry.broken {
+		return true
+	}
+
+	if p.access == nil {
+		p.access = map[string]bool{}
+	}
+
+	ok, cached := p.access[entry.path]
+	if !cached {
+		ok = canAccess(entry.path, entry.isDir)
+		p.access[entry.path] = ok
+	}
+	return ok
+}
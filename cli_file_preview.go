@@ -0,0 +1,362 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// previewMinWidth is the terminal width from which the preview is shown next to the list
+const previewMinWidth = 100
+
+// previewExtensions lists the file types that get syntax coloring in the preview
+var previewExtensions = map[string]bool{
+	".json": true, ".yaml": true, ".yml": true, ".toml": true, ".ini": true,
+	".env": true, ".conf": true, ".cfg": true, ".properties": true, ".tf": true, ".hcl": true,
+}
+
+// renderEntries prints the entry lines, together with the preview if enabled.
+// footer is the number of lines printed after them; the preview only takes the rows
+// the terminal has left, as a frame taller than the terminal cannot be cleared.
+func (p *filePicker) renderEntries(lines []string, footer int) {
+	screen := p.screen
+
+	entry, ok := p.current()
+	if !p.options.preview || !ok || entry.name == ".." {
+		for _, line := range lines {
+			screen.line(line)
+		}
+		return
+	}
+
+	width, height := Size()
+
+	// The cursor ends up on the row after the frame
+	rows := height - 1 - len(screen.widths) - footer
+
+	// Without room for the metadata and some content, only the list is shown
+	if rows < 3 {
+		for _, line := range lines {
+			screen.line(line)
+		}
+		return
+	}
+
+	// Side by side if the terminal is wide enough
+	if width >= previewMinWidth {
+		listWidth := width / 2
+		preview := p.preview(entry, max(len(lines), min(p.maxVisible, rows)))
+
+		for i := 0; i < max(len(lines), len(preview)); i++ {
+			left, right := "", ""
+			if i < len(lines) {
+				left = lines[i]
+			}
+			if i < len(preview) {
+				right = preview[i]
+			}
+			screen.line(padRight(left, listWidth) + themeMuted(" │ ") + right)
+		}
+		return
+	}
+
+	// Below the list otherwise, if there is room for more than the metadata
+	for _, line := range lines {
+		screen.line(line)
+	}
+
+	size := min(8, rows-len(lines)-1)
+	if size < 3 {
+		return
+	}
+
+	screen.line(themeMuted(strings.Repeat("─", min(width, 40))))
+	for _, line := range p.preview(entry, size) {
+		screen.line("  " + line)
+	}
+}
+
+// preview returns up to n lines describing an entry, cached for the highlighted path
+func (p *filePicker) preview(entry fileEntry, n int) []string {
+	if p.previewPath != entry.path || p.previewSize != n {
+		p.previewPath = entry.path
+		p.previewSize = n
+		p.previewLines = buildPreview(p.files, entry, n, p.options.hidden)
+	}
+
+	if len(p.previewLines) > n {
+		return p.previewLines[:n]
+	}
+	return p.previewLines
+}
+
+// buildPreview renders metadata and the content of an entry
+func buildPreview(files fileSystem, entry fileEntry, n int, hidden bool) []string {
+	if entry.isDir {
+		return previewDir(files, entry.path, n, hidden)
+	}
+
+	meta := formatBytes(entry.size)
+	if t := formatModTime(entry.modTime); t != "" {
+		meta += " · " + t
+	}
+	meta += " · " + entry.mode.String()
+
+	lines := []string{themeMuted(meta)}
+
+	// Only regular files are read: opening a FIFO blocks and reading a device may consume its input
+	mode := entry.mode
+	if mode&fs.ModeSymlink != 0 {
+		info, err := files.Stat(entry.path)
+		if err != nil {
+			return lines
+		}
+		mode = info.Mode()
+	}
+	if !mode.IsRegular() {
+		return lines
+	}
+
+	lines = append(lines, "")
+
+	f, err := files.Open(entry.path)
+	if err != nil {
+		return append(lines, themeError(describeError(err)))
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, 8*1024))
+	if err != nil {
+		return append(lines, themeError(describeError(err)))
+	}
+
+	if isBinary(data) {
+		return append(lines, previewHex(data, n-len(lines))...)
+	}
+
+	return append(lines, previewText(data, filepath.Ext(entry.name), n-len(lines))...)
+}
+
+// isBinary guesses whether data is not text
+func isBinary(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+
+	// A multi-byte character may be cut off at the end of the buffer
+	for i := 0; i < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); i++ {
+		data = data[:len(data)-1]
+	}
+
+	return !utf8.Valid(data)
+}
+
+// previewDir lists the first entries of a directory
+func previewDir(files fileSystem, path string, n int, hidden bool) []string {
+	// Huge directories are not read completely just for a preview
+	entries, more, err := readDirLimit(files, path, 1000)
+	if err != nil {
+		return []string{themeError(describeError(err))}
+	}
+
+	var names []string
+	for _, f := range entries {
+		if !hidden && strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+
+		if f.IsDir() {
+			names = append(names, themeAccent(f.Name()+"/"))
+		} else {
+			names = append(names, themeText(f.Name()))
+		}
+	}
+
+	count := strconv.Itoa(len(names))
+	if more {
+		count += "+"
+	}
+
+	lines := []string{themeMuted(count + " items"), ""}
+
+	// Without room for a name and the "more" line, only the count is shown
+	if n-len(lines) < 2 {
+		return lines[:1]
+	}
+
+	if len(names) > n-len(lines) {
+		more := len(names) - (n - len(lines) - 1)
+		names = append(names[:n-len(lines)-1], themeMuted(fmt.Sprintf("… %d more", more)))
+	}
+
+	return append(lines, names...)
+}
+
+// previewHex renders a hex dump of the start of a binary file
+func previewHex(data []byte, n int) []string {
+	const width = 8
+
+	var lines []string
+	for offset := 0; offset < len(data) && len(lines) < n; offset += width {
+		row := data[offset:min(offset+width, len(data))]
+
+		var hex, ascii strings.Builder
+		for i := 0; i < width; i++ {
+			if i < len(row) {
+				fmt.Fprintf(&hex, "%02x ", row[i])
+			} else {
+				hex.WriteString("   ")
+			}
+		}
+		for _, b := range row {
+			if b >= 32 && b < 127 {
+				ascii.WriteByte(b)
+			} else {
+				ascii.WriteByte('.')
+			}
+		}
+
+		lines = append(lines, themeMuted(fmt.Sprintf("%08x  ", offset))+themeText(hex.String())+" "+themeSubtle(ascii.String()))
+	}
+	return lines
+}
+
+// previewText renders the first lines of a text file
+func previewText(data []byte, ext string, n int) []string {
+	highlight := previewExtensions[strings.ToLower(ext)]
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if len(lines) >= n {
+			break
+		}
+
+		line = strings.TrimRight(line, "\r")
+		line = previewClean(line)
+
+		if highlight {
+			lines = append(lines, highlightLine(line))
+		} else {
+			lines = append(lines, themeText(line))
+		}
+	}
+	return lines
+}
+
+// previewClean removes escape sequences and control characters from a line of a file,
+// so its content cannot change the terminal or the size of the preview
+func previewClean(line string) string {
+	line = StripANSI(line)
+	line = strings.ReplaceAll(line, "\t", "    ")
+
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r >= 0x7f && r < 0xa0 {
+			return '�'
+		}
+		return r
+	}, line)
+}
+
+// highlightLine colors keys, strings, numbers and comments of a configuration file line
+func highlightLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, ";") {
+		return themeMuted(line)
+	}
+
+	var sb strings.Builder
+
+	i := configKeyEnd(line)
+	if i > 0 {
+		sb.WriteString(currentTheme.Blue.Color(line[:i]))
+	}
+
+	plain := i
+	flush := func(end int) {
+		if end > plain {
+			sb.WriteString(themeText(line[plain:end]))
+		}
+	}
+
+	for i < len(line) {
+		c := line[i]
+
+		switch {
+		case c == '"' || c == '\'':
+			flush(i)
+			j := i + 1
+			for j < len(line) && line[j] != c {
+				if line[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(line))
+			sb.WriteString(currentTheme.Green.Color(line[i:j]))
+			i, plain = j, j
+
+		case c >= '0' && c <= '9' && (i == 0 || !isWordByte(line[i-1])):
+			flush(i)
+			j := i
+			for j < len(line) && (isWordByte(line[j]) || line[j] == '.') {
+				j++
+			}
+			sb.WriteString(currentTheme.Peach.Color(line[i:j]))
+			i, plain = j, j
+
+		case c == '#' && i > 0 && line[i-1] == ' ':
+			flush(i)
+			sb.WriteString(themeMuted(line[i:]))
+			i, plain = len(line), len(line)
+
+		default:
+			i++
+		}
+	}
+	flush(len(line))
+
+	return sb.String()
+}
+
+// configKeyEnd returns the end of a leading key like `name:`, `"name":` or `name =`, or 0
+func configKeyEnd(line string) int {
+	i := 0
+	for i < len(line) && (line[i] == ' ' || line[i] == '-') {
+		i++
+	}
+
+	start := i
+	if i < len(line) && line[i] == '"' {
+		i++
+		for i < len(line) && line[i] != '"' {
+			i++
+		}
+		i++
+	} else {
+		for i < len(line) && (isWordByte(line[i]) || line[i] == '.' || line[i] == '-') {
+			i++
+		}
+	}
+
+	if i <= start || i > len(line) {
+		return 0
+	}
+
+	end := i
+	for i < len(line) && line[i] == ' ' {
+		i++
+	}
+	if i < len(line) && (line[i] == ':' || line[i] == '=') {
+		return end
+	}
+	return 0
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestPreviewDirSmall(t *testing.T) {
+	files := fsFileSystem{fstest.MapFS{
+		"d/a": {},
+		"d/b": {},
+		"d/c": {},
+	}}
+
+	for n := 0; n <= 6; n++ {
+		lines := previewDir(files, "d", n, false)
+
+		if len(lines) == 0 {
+			t.Errorf("previewDir(n=%d) returned no lines", n)
+		}
+		if len(lines) > max(n, 1) {
+			t.Errorf("previewDir(n=%d) returned %d lines", n, len(lines))
+		}
+	}
+
+	if got := StripANSI(previewDir(files, "d", 4, false)[3]); got != "… 2 more" {
+		t.Errorf("last line = %q, want %q", got, "… 2 more")
+	}
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// saveName returns the entered file name, with the default extension appended if it has none
+func (p *filePicker) saveName() string {
+	name := strings.TrimSpace(p.nameInput)
+
+	if name != "" && filepath.Ext(name) == "" && len(p.types) > 0 {
+		ext := p.types[0]
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		name += ext
+	}
+
+	return name
+}
+
+// save validates the entered file name and asks before overwriting an existing file.
+// It returns the path to save to, or "" if the dialog stays open.
+func (p *filePicker) save() (string, error) {
+	name := p.saveName()
+	if name == "" {
+		p.message = "enter a file name"
+		return "", nil
+	}
+
+	path := filepath.Join(p.dir, name)
+
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		p.message = "directory does not exist: " + filepath.Dir(path)
+		return "", nil
+	}
+
+	if err := checkWritable(filepath.Dir(path)); err != nil {
+		p.message = err.Error()
+		return "", nil
+	}
+
+	info, err := os.Stat(path)
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		p.message = err.Error()
+		return "", nil
+	}
+
+	if err == nil {
+		if info.IsDir() {
+			p.message = name + " is a directory"
+			return "", nil
+		}
+
+		p.screen.clear()
+
+		// The answered question is rendered into the picker's frame, so the next redraw removes it
+		overwrite, err := confirm(promptContext{screen: p.screen}, fmt.Sprintf("%s already exists. Overwrite?", name), false, ConfirmDanger())
+		if err != nil {
+			return "", err
+		}
+
+		p.screen.clear()
+
+		if !overwrite {
+			return "", nil
+		}
+	}
+
+	return path, nil
+}
+
+// handleSaveKey handles a key press in save mode and returns the chosen path, if any.
+// Typing edits the file name; the arrow keys and Tab move the focus to the list.
+func (p *filePicker) handleSaveKey(key int, char rune) ([]string, error) {
+	switch key {
+	case keyEnter:
+		if p.listFocus {
+			entry, ok := p.current()
+			if !ok {
+				break
+			}
+
+			if entry.isDir {
+				p.navigate(entry.path)
+			} else {
+				// Take the name of an existing file to replace it
+				p.nameInput = entry.name
+			}
+
+			p.listFocus = false
+			break
+		}
+
+		path, err := p.save()
+		if err != nil || path == "" {
+			return nil, err
+		}
+		return []string{path}, nil
+
+	case keyTab:
+		p.listFocus = !p.listFocus
+
+	case keyUp:
+		p.listFocus = true
+		if p.selectedIdx > 0 {
+			p.selectedIdx--
+		}
+
+	case keyDown:
+		p.listFocus = true
+		if len(p.filtered) > 0 && p.selectedIdx < len(p.filtered)-1 {
+			p.selectedIdx++
+		}
+
+	case keyLeft:
+		if !p.files.IsRoot(p.dir) {
+			p.navigate(p.files.Dir(p.dir))
+		}
+
+	case keyRight:
+		if entry, ok := p.current(); ok && entry.isDir && p.listFocus {
+			p.navigate(entry.path)
+		}
+
+	case keyHome, keyCtrlG:
+		p.openJumps()
+
+	case keyCtrlT:
+		p.options.hidden = !p.options.hidden
+		p.reload()
+
+	case keyCtrlO:
+		p.options.sort = (p.options.sort + 1) % (FileSortExtension + 1)
+		p.reload()
+
+	case keyBackspace:
+		p.listFocus = false
+		p.nameInput = trimLastGrapheme(p.nameInput)
+
+	case keyCtrlU:
+		p.listFocus = false
+		p.nameInput = ""
+
+	default:
+		if char != 0 && char >= 32 {
+			p.listFocus = false
+			p.nameInput += string(char)
+		}
+	}
+
+	return nil, nil
+}
+
+// SaveFile lets the user browse to a directory and enter a file name to save to.
+// If the name has no extension, the first of types is appended. Existing files are only
+// returned after confirming to overwrite them, and read-only directories are refused.
+func SaveFile(label, defaultName string, types []string, options ...FileOption) (string, error) {
+	p := newFilePicker(osFileSystem{}, label, types, fileModeSave, options)
+	p.nameInput = defaultName
+
+	paths, err := p.run()
+
+	if err != nil {
+		return "", err
+	}
+
+	return paths[0], nil
+}
+
+func MustSaveFile(label, defaultName string, types []string, options ...FileOption) string {
+	value, err := SaveFile(label, defaultName, types, options...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return value
+}
@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// errBack is returned by a prompt of a Form when the user wants to return to the previous field
+var errBack = errors.New("back")
+
+// promptContext is passed to prompts that are part of a larger flow like a Form
+type promptContext struct {
+	// screen receives the answered prompt, so it can be removed again; if nil, the answer stays on screen
+	screen *frame
+
+	// back makes Esc and Shift+Tab return errBack
+	back bool
+}
+
+func (c promptContext) frame() *frame {
+	if c.screen != nil {
+		return c.screen
+	}
+	return &frame{}
+}
+
+// Answers holds the values entered in a Form by field name.
+// Input fields hold a string, Select fields the chosen item and Confirm fields a bool.
+type Answers map[string]any
+
+// String returns the value of a field as string
+func (a Answers) String(name string) string {
+	switch v := a[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Bool returns the value of a Confirm field
+func (a Answers) Bool(name string) bool {
+	v, _ := a[name].(bool)
+	return v
+}
+
+// Decode stores the answers in the struct v points to.
+// A field receives the answer named by its `form` tag, or by its name, ignoring case.
+func (a Answers) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("decode target must be a pointer to a struct")
+	}
+	rv = rv.Elem()
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := field.Tag.Lookup("form")
+		if name == "-" {
+			continue
+		}
+		if !ok {
+			name = field.Name
+		}
+
+		for key, value := range a {
+			if !strings.EqualFold(key, name) || value == nil {
+				continue
+			}
+
+			val := reflect.ValueOf(value)
+			if !val.Type().ConvertibleTo(field.Type) {
+				return fmt.Errorf("cannot store %s (%T) in field %s (%s)", key, value, field.Name, field.Type)
+			}
+
+			rv.Field(i).Set(val.Convert(field.Type))
+		}
+	}
+
+	return nil
+}
+
+// FormField is a single question of a Form
+type FormField struct {
+	name  string
+	label string
+	value any
+
+	ask    func(ctx promptContext, value any) (any, error)
+	format func(value any) string
+}
+
+// FormInput asks for a line of text, like Input
+func FormInput(name, label, placeholder string) FormField {
+	return FormField{
+		name:  name,
+		label: label,
+		value: "",
+
+		ask: func(ctx promptContext, value any) (any, error) {
+			return input(ctx, label, inputOptions{placeholder: placeholder, value: value.(string)})
+		},
+		format: func(value any) string {
+			return value.(string)
+		},
+	}
+}
+
+// FormSelect asks to pick one of items, like Select
+func FormSelect(name, label string, items []string) FormField {
+	var value any
+	if len(items) > 0 {
+		value = items[0]
+	}
+
+	return FormField{
+		name:  name,
+		label: label,
+		value: value,
+
+		ask: func(ctx promptContext, value any) (any, error) {
+			selected := 0
+			for i, item := range items {
+				if item == value {
+					selected = i
+				}
+			}
+
+			index, err := selectItem(ctx, label, items, selected)
+			if err != nil {
+				return nil, err
+			}
+
+			return items[index], nil
+		},
+		format: func(value any) string {
+			return fmt.Sprint(value)
+		},
+	}
+}
+
+// FormConfirm asks a yes/no question, like Confirm
+func FormConfirm(name, label string, defaultValue bool, options ...ConfirmOption) FormField {
+	o := &confirmOptions{
+		yes: "yes",
+		no:  "no",
+	}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	return FormField{
+		name:  name,
+		label: label,
+		value: defaultValue,
+
+		ask: func(ctx promptContext, value any) (any, error) {
+			return confirm(ctx, label, value.(bool), options...)
+		},
+		format: func(value any) string {
+			if value.(bool) {
+				return o.yes
+			}
+			return o.no
+		},
+	}
+}
+
+// Form asks the fields one after another. Esc or Shift+Tab returns to the previous field.
+// Once all fields are answered, a summary lets the user submit or change single answers.
+func Form(fields ...FormField) (Answers, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("no fields to ask")
+	}
+
+	answers := Answers{}
+
+	screens := make([]*frame, len(fields))
+	for i := range screens {
+		screens[i] = &frame{}
+	}
+
+	// ask prompts for a field, starting with its previous answer or its default
+	ask := func(i int, back bool) error {
+		field := fields[i]
+
+		value, ok := answers[field.name]
+		if !ok {
+			value = field.value
+		}
+
+		value, err := field.ask(promptContext{screen: screens[i], back: back}, value)
+		if err != nil {
+			return err
+		}
+
+		answers[field.name] = value
+		return nil
+	}
+
+	for i := 0; i < len(fields); {
+		err := ask(i, i > 0)
+
+		if errors.Is(err, errBack) {
+			i--
+			screens[i].clear()
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		i++
+	}
+
+	for i := len(screens) - 1; i >= 0; i-- {
+		screens[i].clear()
+	}
+
+	summary := &frame{}
+	choice := &frame{}
+
+	for {
+		rows := make([][]string, len(fields))
+		items := []string{"Submit"}
+
+		for i, field := range fields {
+			rows[i] = []string{field.label, field.format(answers[field.name])}
+			items = append(items, "Edit: "+field.label)
+		}
+
+		for _, line := range tableLines([]string{"Field", "Value"}, rows) {
+			summary.line(line)
+		}
+
+		index, err := selectItem(promptContext{screen: choice}, "", items, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		choice.clear()
+
+		if index == 0 {
+			return answers, nil
+		}
+
+		summary.clear()
+
+		// Esc returns to the summary without changing the answer
+		if err := ask(index-1, true); err != nil && !errors.Is(err, errBack) {
+			return nil, err
+		}
+
+		screens[index-1].clear()
+	}
+}
+
+func MustForm(fields ...FormField) Answers {
+	answers, err := Form(fields...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return answers
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// gitIgnore matches paths against the .gitignore files of a git repository
+type gitIgnore struct {
+	mu sync.Mutex
+
+	root  string
+	rules []gitIgnoreRule
+
+	// loaded tracks the directories whose .gitignore was read
+	loaded map[string]bool
+}
+
+// gitIgnoreRule is a single pattern line of a .gitignore file
+type gitIgnoreRule struct {
+	base     string // directory of the .gitignore, relative to the root
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// findGitRoot returns the root of the git repository containing dir, or ""
+func findGitRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func newGitIgnore(root string) *gitIgnore {
+	return &gitIgnore{
+		root:   root,
+		loaded: map[string]bool{},
+	}
+}
+
+// load reads the .gitignore of a directory, relative to the root
+func (g *gitIgnore) load(rel string) {
+	if g.loaded[rel] {
+		return
+	}
+	g.loaded[rel] = true
+
+	f, err := os.Open(filepath.Join(g.root, filepath.FromSlash(rel), ".gitignore"))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := gitIgnoreRule{base: rel}
+
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, "\\")
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		// Patterns containing a slash are relative to the .gitignore, others match at any depth
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+
+		rule.pattern = line
+		g.rules = append(g.rules, rule)
+	}
+}
+
+// ignored reports whether an absolute path is excluded by the repository's .gitignore files
+func (g *gitIgnore) ignored(p string, isDir bool) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.match(p, isDir)
+}
+
+func (g *gitIgnore) match(p string, isDir bool) bool {
+	rel, err := filepath.Rel(g.root, p)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	if rel == ".git" {
+		return true
+	}
+
+	// Everything inside an ignored directory is ignored as well
+	if parent := filepath.Dir(p); parent != g.root && g.match(parent, true) {
+		return true
+	}
+
+	// Read the .gitignore of every directory from the root down to the path
+	dir := ""
+	g.load(dir)
+	for _, part := range strings.Split(path.Dir(rel), "/") {
+		if part == "." {
+			break
+		}
+		dir = path.Join(dir, part)
+		g.load(dir)
+	}
+
+	// The last matching rule wins
+	result := false
+	for _, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.matches(rel) {
+			result = !rule.negate
+		}
+	}
+	return result
+}
+
+func (r gitIgnoreRule) matches(rel string) bool {
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = strings.TrimPrefix(rel, r.base+"/")
+	}
+
+	if !r.anchored {
+		return matchGlob(r.pattern, path.Base(rel))
+	}
+
+	return matchSegments(strings.Split(r.pattern, "/"), strings.Split(rel, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where ** matches any number of segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 || !matchGlob(pattern[0], segments[0]) {
+		return false
+	}
+
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// matchGlob matches a single name against a shell pattern, treating malformed patterns as no match
+func matchGlob(pattern, name string) bool {
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
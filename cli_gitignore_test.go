@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitIgnoreIgnored(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		".gitignore":     "# build output\n*.log\n!keep.log\n/dist\nnode_modules/\ndocs/*.tmp\n",
+		"src/.gitignore": "generated.go\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "main.go", want: false},
+		{path: ".git", isDir: true, want: true},
+
+		// Patterns without a slash match at any depth, the last matching rule wins
+		{path: "debug.log", want: true},
+		{path: "src/trace.log", want: true},
+		{path: "keep.log", want: false},
+
+		// Anchored patterns only match relative to their .gitignore
+		{path: "dist", isDir: true, want: true},
+		{path: "src/dist", isDir: true, want: false},
+		{path: "docs/a.tmp", want: true},
+		{path: "docs/sub/a.tmp", want: false},
+
+		// Directory patterns don't match files, but everything inside an ignored directory
+		{path: "node_modules", isDir: true, want: true},
+		{path: "node_modules", want: false},
+		{path: "node_modules/pkg/index.js", want: true},
+		{path: "dist/app.js", want: true},
+
+		// Nested .gitignore files apply to their directory
+		{path: "src/generated.go", want: true},
+		{path: "generated.go", want: false},
+	}
+
+	g := newGitIgnore(root)
+
+	for _, tt := range tests {
+		path := filepath.Join(root, filepath.FromSlash(tt.path))
+		if got := g.ignored(path, tt.isDir); got != tt.want {
+			t.Errorf("ignored(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	// Paths outside of the repository are never ignored
+	if g.ignored(filepath.Join(filepath.Dir(root), "debug.log"), false) {
+		t.Error("path outside of the repository is ignored")
+	}
+}
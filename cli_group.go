@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GroupOption configures RunGroup
+type GroupOption func(*groupOptions)
+
+type groupOptions struct {
+	limit int
+}
+
+// GroupLimit runs at most limit jobs at a time; by default all jobs run at once
+func GroupLimit(limit int) GroupOption {
+	return func(o *groupOptions) {
+		o.limit = limit
+	}
+}
+
+// TaskError is the error of a single failed task
+type TaskError struct {
+	Title string
+	Err   error
+}
+
+func (e TaskError) Error() string {
+	return e.Title + ": " + e.Err.Error()
+}
+
+func (e TaskError) Unwrap() error {
+	return e.Err
+}
+
+// GroupError lists the tasks of RunGroup that failed
+type GroupError struct {
+	Total  int
+	Failed []TaskError
+}
+
+func (e *GroupError) Error() string {
+	messages := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		messages[i] = f.Error()
+	}
+
+	return fmt.Sprintf("%d of %d tasks failed: %s", len(e.Failed), e.Total, strings.Join(messages, "; "))
+}
+
+func (e *GroupError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f
+	}
+	return errs
+}
+
+// groupState tracks a task of RunGroup
+type groupState struct {
+	task *Task
+
+	started  time.Time
+	finished time.Time
+	err      error
+}
+
+// RunGroup runs jobs concurrently and shows a spinner line per job. It waits for all jobs
+// and returns a *GroupError listing the failed ones; skipped jobs and warnings are not failures.
+// Ctrl+C cancels the running jobs and skips the waiting ones, like RunContext.
+func RunGroup(ctx context.Context, jobs []Job, options ...GroupOption) error {
+	o := &groupOptions{}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Ctrl+C cancels the tasks instead of killing the process
+	signals, stop := notifyInterrupt()
+	defer stop()
+
+	var mu sync.Mutex
+	var fnPanic any
+
+	states := make([]*groupState, len(jobs))
+	for i, t := range jobs {
+		states[i] = &groupState{task: newTask(t.Title)}
+	}
+
+	var slots chan struct{}
+	if o.limit > 0 {
+		slots = make(chan struct{}, o.limit)
+	}
+
+	var wg sync.WaitGroup
+
+	for i, t := range jobs {
+		state := states[i]
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			finish := func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				state.err = err
+				state.finished = time.Now()
+			}
+
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+					finish(ctx.Err())
+					return
+				}
+			}
+
+			if err := ctx.Err(); err != nil {
+				finish(err)
+				return
+			}
+
+			mu.Lock()
+			state.started = time.Now()
+			mu.Unlock()
+
+			// A panic is raised again by the caller, once the terminal is restored
+			defer func() {
+				if r := recover(); r != nil {
+					mu.Lock()
+					if fnPanic == nil {
+						fnPanic = r
+					}
+					mu.Unlock()
+
+					finish(fmt.Errorf("panic: %v", r))
+				}
+			}()
+
+			finish(t.Run(ctx, state.task))
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Hide cursor
+	plain := plainOutput()
+	if !plain {
+		hideCursor()
+		defer showCursor()
+	}
+
+	screen := &frame{plain: plain}
+	index := 0
+	ticker := time.NewTicker(80 * time.Millisecond)
+	defer ticker.Stop()
+
+	var interrupted bool
+	var grace <-chan time.Time
+
+	// Without a terminal, tasks are logged when they start and finish, as they run concurrently
+	// their output is not put in sections
+	started := make([]bool, len(states))
+	finished := make([]bool, len(states))
+	heartbeat := time.Now()
+	cancelling := false
+
+	drawPlain := func() {
+		if interrupted && !cancelling {
+			cancelling = true
+			screen.line(themeWarning("  cancelling…"))
+		}
+
+		var running []string
+
+		for i, state := range states {
+			title, suffix, _ := state.task.status()
+
+			if !state.started.IsZero() && !started[i] {
+				started[i] = true
+				screen.line(themeHighlight("▸") + " " + themeText(title))
+			}
+
+			for _, log := range state.task.takeLogs() {
+				screen.line(themeMuted(title+": ") + themeText(log))
+			}
+
+			switch {
+			case state.finished.IsZero():
+				if !state.started.IsZero() {
+					running = append(running, title+suffix+" "+formatDuration(time.Since(state.started)))
+				}
+
+			case finished[i]:
+				// Already reported
+
+			case state.started.IsZero() || errors.Is(state.err, context.Canceled) && ctx.Err() != nil:
+				finished[i] = true
+				screen.line(themeMuted(currentSymbols.Skipped + " " + title + " (cancelled)"))
+
+			default:
+				finished[i] = true
+				screen.line(resultLine(title, state.err, " "+formatDuration(state.finished.Sub(state.started))))
+			}
+		}
+
+		if len(running) > 0 && time.Since(heartbeat) >= plainHeartbeat {
+			heartbeat = time.Now()
+			screen.line(themeMuted("  running: " + strings.Join(running, ", ")))
+		}
+	}
+
+	draw := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if plain {
+			drawPlain()
+			return
+		}
+
+		screen.clear()
+
+		for _, state := range states {
+			for _, log := range state.task.takeLogs() {
+				fmt.Print("\r\033[K" + themeText(strings.ReplaceAll(log, "\n", "\r\n")) + "\r\n")
+			}
+		}
+
+		spinner := themeHighlight(string(spinnerFrames[index]))
+
+		for _, state := range states {
+			title, suffix, detail := state.task.status()
+			if detail != "" {
+				suffix += " " + detail
+			}
+
+			switch {
+			case state.started.IsZero() && state.finished.IsZero():
+				screen.line(themeMuted("○ " + title + " (waiting)"))
+
+			case state.finished.IsZero():
+				if interrupted {
+					suffix += " cancelling…"
+				}
+				elapsed := " " + formatDuration(time.Since(state.started))
+				screen.line(spinner + " " + themeText(title) + themeMuted(suffix+elapsed))
+
+			case state.started.IsZero() || errors.Is(state.err, context.Canceled) && ctx.Err() != nil:
+				screen.line(themeMuted(currentSymbols.Skipped + " " + title + " (cancelled)"))
+
+			default:
+				elapsed := " " + formatDuration(state.finished.Sub(state.started))
+				screen.line(resultLine(title, state.err, elapsed))
+			}
+		}
+
+		index = (index + 1) % len(spinnerFrames)
+	}
+
+	// result collects the failed tasks
+	result := func() error {
+		if fnPanic != nil {
+			panic(fnPanic)
+		}
+
+		if interrupted {
+			return ErrUserAborted
+		}
+
+		err := &GroupError{Total: len(jobs)}
+		for _, state := range states {
+			if isFailure(state.err) {
+				title, _, _ := state.task.status()
+				err.Failed = append(err.Failed, TaskError{Title: title, Err: state.err})
+			}
+		}
+
+		if len(err.Failed) == 0 {
+			return nil
+		}
+		return err
+	}
+
+	draw()
+
+	for {
+		select {
+		case <-done:
+			draw()
+			return result()
+
+		case <-signals:
+			if interrupted {
+				// A second Ctrl+C stops waiting for the tasks
+				grace = time.After(0)
+				break
+			}
+
+			interrupted = true
+			grace = time.After(runGracePeriod)
+			cancel()
+
+		case <-grace:
+			draw()
+			screen.line(themeWarning(currentSymbols.Failure) + " " + themeMuted("cancelled, cleanup did not finish"))
+			return ErrUserAborted
+
+		case <-ticker.C:
+			draw()
+		}
+	}
+}
+
+func MustRunGroup(ctx context.Context, jobs []Job, options ...GroupOption) error {
+	err := RunGroup(ctx, jobs, options...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return err
+}
@@ -3,52 +3,113 @@ package cli
 import (
 	"fmt"
 	"os"
-	"unicode/utf8"
+	"strings"
 )
 
 func Input(label, placeholder string) (string, error) {
+	return input(promptContext{}, label, inputOptions{placeholder: placeholder})
+}
+
+// Password reads a line without showing the typed characters
+func Password(label string) (string, error) {
+	return input(promptContext{}, label, inputOptions{mask: true})
+}
+
+// inputOptions configures the line read by input
+type inputOptions struct {
+	placeholder string
+	value       string
+
+	// mask hides the typed characters
+	mask bool
+
+	// validate rejects the entered text with an error shown above the prompt
+	validate func(string) error
+}
+
+// input reads a single line
+func input(ctx promptContext, label string, o inputOptions) (string, error) {
 	var result string
 
+	screen := ctx.frame()
+	placeholder := o.placeholder
+
+	display := func(s string) string {
+		if o.mask {
+			return strings.Repeat("•", len(graphemes(s)))
+		}
+		return s
+	}
+
 	err := withRawMode(func() error {
-		buffer := ""
+		buffer := o.value
+		message := ""
+
+		keys := newKeyReader(os.Stdin)
+		defer keys.Close()
+
+		prompt := themeAccent(bold(label)) + themeAccent(": ")
 
 		redraw := func() {
-			clearLine()
-			prompt := themeAccent(bold(label)) + themeAccent(": ")
+			if message != "" {
+				screen.line(themeError("✗ " + message))
+			}
+
 			if placeholder != "" && buffer == "" {
-				fmt.Print(prompt + themeSubtle(placeholder))
+				screen.inline(prompt + themeSubtle(placeholder))
 			} else {
-				fmt.Print(prompt + themeText(buffer))
+				// Keep the end of the buffer visible, the cursor sits behind it
+				width, _ := Size()
+				text := truncateLeft(display(buffer), width-1-VisibleWidth(prompt))
+				screen.inline(prompt + themeText(text))
 			}
 		}
 
 		redraw()
 
 		for {
-			key, char, err := readKey(os.Stdin)
+			key, char, err := keys.Read()
 			if err != nil {
 				return err
 			}
 
+			if key != keyResize {
+				message = ""
+			}
+
 			switch key {
 			case keyCtrlC:
 				fmt.Print("\r\n")
 				return ErrUserAborted
 
 			case keyEnter:
-				if buffer == "" && placeholder != "" {
-					buffer = placeholder
+				value := buffer
+				if value == "" && placeholder != "" {
+					value = placeholder
 				}
-				result = buffer
-				fmt.Print("\r\n")
+
+				if o.validate != nil {
+					if err := o.validate(value); err != nil {
+						message = err.Error()
+						break
+					}
+				}
+
+				result = value
+
+				screen.clear()
+				screen.line(prompt + themeText(display(result)))
 				return nil
 
-			case keyBackspace:
-				if len(buffer) > 0 {
-					_, size := utf8.DecodeLastRuneInString(buffer)
-					buffer = buffer[:len(buffer)-size]
+			case keyEscape, keyShiftTab:
+				if ctx.back {
+					screen.clear()
+					return errBack
 				}
 
+			case keyBackspace:
+				buffer = trimLastGrapheme(buffer)
+
 			case keyCtrlU:
 				buffer = ""
 
@@ -58,6 +119,7 @@ func Input(label, placeholder string) (string, error) {
 				}
 			}
 
+			screen.clear()
 			redraw()
 		}
 	})
@@ -78,3 +140,13 @@ func MustInput(label, placeholder string) string {
 
 	return value
 }
+
+func MustPassword(label string) string {
+	value, err := Password(label)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return value
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"io"
 	"os"
+	"unicode/utf8"
 
 	"golang.org/x/term"
 )
@@ -14,6 +15,7 @@ const (
 	keyShiftEnter
 	keyBackspace
 	keyTab
+	keyShiftTab
 	keyEscape
 	keySpace
 	keyUp
@@ -27,23 +29,30 @@ const (
 	keyCtrlC
 	keyCtrlD
 	keyCtrlE
+	keyCtrlG
 	keyCtrlJ
 	keyCtrlK
+	keyCtrlN
+	keyCtrlO
+	keyCtrlP
+	keyCtrlS
+	keyCtrlT
 	keyCtrlU
 	keyCtrlW
 	keyAltEnter
-)
 
-// readKey reads a single key press and returns the key code and rune
-func readKey(r io.Reader) (key int, char rune, err error) {
-	buf := make([]byte, 4)
-	n, err := r.Read(buf)
-	if err != nil {
-		return keyUnknown, 0, err
-	}
+	// keyResize is reported by a keyReader when the terminal was resized
+	keyResize
+
+	// keyRefresh is reported by a keyReader when Refresh was called, e.g. by background work
+	keyRefresh
+)
 
-	if n == 0 {
-		return keyUnknown, 0, nil
+// parseKey parses the key press at the start of buf and returns its key code, rune and length in bytes.
+// The length is 0 if buf is empty or ends within a character, which then needs more input.
+func parseKey(buf []byte) (key int, char rune, size int) {
+	if len(buf) == 0 {
+		return keyUnknown, 0, 0
 	}
 
 	b := buf[0]
@@ -51,48 +60,179 @@ func readKey(r io.Reader) (key int, char rune, err error) {
 	// Control characters
 	switch b {
 	case 1: // Ctrl+A
-		return keyCtrlA, 0, nil
+		return keyCtrlA, 0, 1
 	case 3: // Ctrl+C
-		return keyCtrlC, 0, nil
+		return keyCtrlC, 0, 1
 	case 4: // Ctrl+D
-		return keyCtrlD, 0, nil
+		return keyCtrlD, 0, 1
 	case 5: // Ctrl+E
-		return keyCtrlE, 0, nil
+		return keyCtrlE, 0, 1
+	case 7: // Ctrl+G
+		return keyCtrlG, 0, 1
 	case 9: // Tab
-		return keyTab, '\t', nil
+		return keyTab, '\t', 1
 	case 10: // Ctrl+J (line feed)
-		return keyCtrlJ, '\n', nil
+		return keyCtrlJ, '\n', 1
 	case 11: // Ctrl+K
-		return keyCtrlK, 0, nil
+		return keyCtrlK, 0, 1
 	case 13: // Enter (CR)
-		return keyEnter, '\n', nil
+		return keyEnter, '\n', 1
+	case 14: // Ctrl+N
+		return keyCtrlN, 0, 1
+	case 15: // Ctrl+O
+		return keyCtrlO, 0, 1
+	case 16: // Ctrl+P
+		return keyCtrlP, 0, 1
+	case 19: // Ctrl+S
+		return keyCtrlS, 0, 1
+	case 20: // Ctrl+T
+		return keyCtrlT, 0, 1
 	case 21: // Ctrl+U
-		return keyCtrlU, 0, nil
+		return keyCtrlU, 0, 1
 	case 23: // Ctrl+W
-		return keyCtrlW, 0, nil
+		return keyCtrlW, 0, 1
 	case 27: // Escape sequence
+		n := keySequenceLen(buf)
 		if n == 1 {
-			return keyEscape, 0, nil
+			return keyEscape, 0, 1
 		}
-		return parseEscapeSequence(buf[:n])
+		key, char, _ := parseEscapeSequence(buf[:n])
+		return key, char, n
 	case 32: // Space
-		return keySpace, ' ', nil
+		return keySpace, ' ', 1
 	case 127: // Backspace (DEL)
-		return keyBackspace, 0, nil
+		return keyBackspace, 0, 1
 	}
 
 	// Regular ASCII characters
 	if b >= 32 && b < 127 {
-		return keyUnknown, rune(b), nil
+		return keyUnknown, rune(b), 1
+	}
+
+	// Other control characters
+	if b < 0x80 {
+		return keyUnknown, 0, 1
+	}
+
+	// UTF-8 multi-byte characters, possibly cut off at the end of the input read so far
+	if !utf8.FullRune(buf) {
+		return keyUnknown, 0, 0
+	}
+
+	r, n := utf8.DecodeRune(buf)
+	if r == utf8.RuneError {
+		return keyUnknown, 0, n
+	}
+
+	return keyUnknown, r, n
+}
+
+// keySequenceLen returns the length of the escape sequence at the start of buf
+func keySequenceLen(buf []byte) int {
+	switch {
+	case len(buf) < 2:
+		return 1
+	case buf[1] == '[':
+		return escapeLen(string(buf))
+	case buf[1] == 'O':
+		// SS3 sequences, e.g. ESC O A
+		return min(3, len(buf))
+	default:
+		return 2
+	}
+}
+
+// keyEvent is the result of a single readKey call
+type keyEvent struct {
+	key  int
+	char rune
+	err  error
+}
+
+// keyReader reads key presses in the background so that a prompt
+// can redraw itself when the terminal is resized while it waits for input
+type keyReader struct {
+	r       io.Reader
+	events  chan keyEvent
+	pending bool
+
+	// buf holds input that was read but not returned yet, e.g. the rest of a paste.
+	// It is only used by the goroutine reading from r.
+	buf []byte
+
+	resize  <-chan struct{}
+	refresh chan struct{}
+	stop    func()
+}
+
+func newKeyReader(r io.Reader) *keyReader {
+	resize, stop := watchResize()
+
+	return &keyReader{
+		r:      r,
+		events: make(chan keyEvent, 1),
+
+		resize:  resize,
+		refresh: make(chan struct{}, 1),
+		stop:    stop,
+	}
+}
+
+// Refresh makes a pending Read return keyRefresh, so the prompt redraws. It is safe to call from any goroutine.
+func (k *keyReader) Refresh() {
+	select {
+	case k.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// Read returns the next key press, or keyResize or keyRefresh if the prompt should redraw first
+func (k *keyReader) Read() (key int, char rune, err error) {
+	// Only one read is in flight at a time, so no input is lost to a resize
+	if !k.pending {
+		k.pending = true
+
+		go func() {
+			key, char, err := k.readKey()
+			k.events <- keyEvent{key, char, err}
+		}()
+	}
+
+	select {
+	case e := <-k.events:
+		k.pending = false
+		return e.key, e.char, e.err
+
+	case <-k.resize:
+		return keyResize, 0, nil
+
+	case <-k.refresh:
+		return keyRefresh, 0, nil
 	}
+}
+
+// readKey returns the next key press. Pasted text and text committed by an input method
+// arrive in a single read; they are returned one character at a time.
+func (k *keyReader) readKey() (key int, char rune, err error) {
+	for {
+		if key, char, n := parseKey(k.buf); n > 0 {
+			k.buf = k.buf[n:]
+			return key, char, nil
+		}
 
-	// UTF-8 multi-byte characters
-	if b >= 0xC0 {
-		r := decodeUTF8(buf[:n])
-		return keyUnknown, r, nil
+		buf := make([]byte, 256)
+		n, err := k.r.Read(buf)
+		if err != nil {
+			return keyUnknown, 0, err
+		}
+
+		k.buf = append(k.buf, buf[:n]...)
 	}
+}
 
-	return keyUnknown, 0, nil
+// Close stops watching for resize events
+func (k *keyReader) Close() {
+	k.stop()
 }
 
 // parseEscapeSequence parses ANSI escape sequences
@@ -140,6 +280,8 @@ func parseEscapeSequence(buf []byte) (key int, char rune, err error) {
 			return keyHome, 0, nil
 		case 'F':
 			return keyEnd, 0, nil
+		case 'Z':
+			return keyShiftTab, 0, nil
 		case '3':
 			if len(buf) > 3 && buf[3] == '~' {
 				return keyDelete, 0, nil
@@ -192,37 +334,6 @@ func splitCSI(s string) []int {
 	return result
 }
 
-// decodeUTF8 decodes a UTF-8 sequence from bytes
-func decodeUTF8(buf []byte) rune {
-	if len(buf) == 0 {
-		return 0
-	}
-
-	b := buf[0]
-
-	// 1-byte (ASCII)
-	if b < 0x80 {
-		return rune(b)
-	}
-
-	// 2-byte
-	if b < 0xE0 && len(buf) >= 2 {
-		return rune(b&0x1F)<<6 | rune(buf[1]&0x3F)
-	}
-
-	// 3-byte
-	if b < 0xF0 && len(buf) >= 3 {
-		return rune(b&0x0F)<<12 | rune(buf[1]&0x3F)<<6 | rune(buf[2]&0x3F)
-	}
-
-	// 4-byte
-	if len(buf) >= 4 {
-		return rune(b&0x07)<<18 | rune(buf[1]&0x3F)<<12 | rune(buf[2]&0x3F)<<6 | rune(buf[3]&0x3F)
-	}
-
-	return 0
-}
-
 // withRawMode executes a function with the terminal in raw mode
 func withRawMode(fn func() error) error {
 	fd := int(os.Stdin.Fd())
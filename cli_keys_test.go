@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"io"
+	"testing"
+)
+
+// chunkReader returns its chunks one per Read, like a terminal delivering input
+type chunkReader struct {
+	chunks []string
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+
+	return n, nil
+}
+
+func TestKeyReaderReadKey(t *testing.T) {
+	type key struct {
+		key  int
+		char rune
+	}
+
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []key
+	}{
+		{name: "ascii", chunks: []string{"a", "b"}, want: []key{{keyUnknown, 'a'}, {keyUnknown, 'b'}}},
+		{name: "paste", chunks: []string{"日本"}, want: []key{{keyUnknown, '日'}, {keyUnknown, '本'}}},
+		{name: "variation selector", chunks: []string{"❤\ufe0f"}, want: []key{{keyUnknown, '❤'}, {keyUnknown, '\ufe0f'}}},
+		{name: "split character", chunks: []string{"x\xe6", "\x97", "\xa5"}, want: []key{{keyUnknown, 'x'}, {keyUnknown, '日'}}},
+		{name: "escape sequences", chunks: []string{"\033[A\033[Bq"}, want: []key{{keyUp, 0}, {keyDown, 0}, {keyUnknown, 'q'}}},
+		{name: "ss3", chunks: []string{"\033OCz"}, want: []key{{keyRight, 0}, {keyUnknown, 'z'}}},
+		{name: "shift enter", chunks: []string{"\033[13;2u"}, want: []key{{keyShiftEnter, '\n'}}},
+		{name: "escape", chunks: []string{"\033"}, want: []key{{keyEscape, 0}}},
+		{name: "controls", chunks: []string{"\x03\r\x7f"}, want: []key{{keyCtrlC, 0}, {keyEnter, '\n'}, {keyBackspace, 0}}},
+		{name: "invalid", chunks: []string{"\xff!"}, want: []key{{keyUnknown, 0}, {keyUnknown, '!'}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &keyReader{r: &chunkReader{chunks: tt.chunks}}
+
+			for i, want := range tt.want {
+				code, char, err := k.readKey()
+				if err != nil {
+					t.Fatalf("key %d: %v", i, err)
+				}
+				if code != want.key || char != want.char {
+					t.Errorf("key %d = (%d, %q), want (%d, %q)", i, code, char, want.key, want.char)
+				}
+			}
+
+			if _, _, err := k.readKey(); err != io.EOF {
+				t.Errorf("input left after the expected keys, err = %v", err)
+			}
+		})
+	}
+}
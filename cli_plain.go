@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// plainHeartbeat is how often a running task is reported in plain output, so long tasks
+// don't look stuck in a log
+const plainHeartbeat = 30 * time.Second
+
+// plainOutput reports whether stdout is not a terminal, e.g. redirected to a file or a CI log.
+// Spinners and progress bars then write plain log lines instead of redrawing a frame.
+var plainOutput = sync.OnceValue(func() bool {
+	return !isTerminalCheck()
+})
+
+// ciSystem is a CI service whose log viewer supports collapsible sections
+type ciSystem int
+
+const (
+	ciNone ciSystem = iota
+	ciGitHub
+	ciGitLab
+)
+
+var detectCI = sync.OnceValue(func() ciSystem {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return ciGitHub
+	case os.Getenv("GITLAB_CI") == "true":
+		return ciGitLab
+	default:
+		return ciNone
+	}
+})
+
+// plainLine writes s as a line of a log. Colors are kept for CI log viewers, which render them.
+func plainLine(s string) {
+	if detectCI() == ciNone {
+		s = StripANSI(s)
+	}
+
+	os.Stdout.WriteString(s + "\n")
+}
+
+// logSection is a collapsible section of a CI log
+type logSection struct {
+	name string
+}
+
+var logSections atomic.Int64
+
+// startSection opens a collapsible section with the given title. Outside of CI, only the title is written.
+func startSection(title string) *logSection {
+	s := &logSection{
+		name: "section_" + strconv.FormatInt(logSections.Add(1), 10),
+	}
+
+	switch detectCI() {
+	case ciGitHub:
+		plainLine("::group::" + StripANSI(title))
+	case ciGitLab:
+		fmt.Printf("\033[0Ksection_start:%d:%s[collapsed=true]\r\033[0K%s\n", time.Now().Unix(), s.name, title)
+	default:
+		plainLine(themeHighlight("▸") + " " + themeText(title))
+	}
+
+	return s
+}
+
+// end closes the section; the outcome of a task is written after it, so it stays visible when collapsed
+func (s *logSection) end() {
+	switch detectCI() {
+	case ciGitHub:
+		plainLine("::endgroup::")
+	case ciGitLab:
+		fmt.Printf("\033[0Ksection_end:%d:%s\r\033[0K\n", time.Now().Unix(), s.name)
+	}
+}
+
+// plainTask reports a task running in spin as log lines: its logs as they arrive,
+// a heartbeat with its status from time to time and when it is being cancelled
+type plainTask struct {
+	task    *Task
+	section *logSection
+
+	start     time.Time
+	heartbeat time.Time
+
+	cancelling bool
+}
+
+func newPlainTask(task *Task) *plainTask {
+	title, _, _ := task.status()
+
+	return &plainTask{
+		task:    task,
+		section: startSection(title),
+
+		start:     time.Now(),
+		heartbeat: time.Now(),
+	}
+}
+
+// update writes what happened since the last call
+func (p *plainTask) update(interrupted bool) {
+	p.flush()
+
+	if interrupted && !p.cancelling {
+		p.cancelling = true
+		plainLine(themeWarning("  cancelling…"))
+	}
+
+	if time.Since(p.heartbeat) < plainHeartbeat {
+		return
+	}
+
+	p.heartbeat = time.Now()
+
+	title, suffix, detail := p.task.status()
+	if detail != "" {
+		suffix += " · " + detail
+	}
+
+	plainLine(themeMuted("  " + title + suffix + " · running for " + formatDuration(time.Since(p.start))))
+}
+
+// finish writes the remaining logs and closes the section
+func (p *plainTask) finish() {
+	p.flush()
+	p.section.end()
+}
+
+func (p *plainTask) flush() {
+	for _, log := range p.task.takeLogs() {
+		plainLine(themeText(log))
+	}
+}
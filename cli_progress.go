@@ -0,0 +1,352 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Partial block characters, from one eighth to a full block
+var progressBlocks = []rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+
+// ProgressOption configures a Progress
+type ProgressOption func(*progressOptions)
+
+type progressOptions struct {
+	bytes bool
+}
+
+// ProgressBytes shows the current value, total and rate as byte sizes, e.g. for downloads
+func ProgressBytes() ProgressOption {
+	return func(o *progressOptions) {
+		o.bytes = true
+	}
+}
+
+// Progress shows a progress bar with percentage, rate, elapsed time and ETA.
+// It is safe for concurrent use; Done or Fail must be called once the work is finished.
+// Ctrl+C while the bar is shown restores the cursor and is then handled as if the bar
+// did not catch it.
+type Progress struct {
+	mu sync.Mutex
+
+	title   string
+	total   int64
+	current int64
+	options progressOptions
+
+	start time.Time
+	stop  chan struct{}
+	done  chan struct{}
+
+	screen *frame
+
+	// Ctrl+C while the bar is shown restores the cursor before it is raised again
+	signals     <-chan os.Signal
+	stopSignals func()
+	interrupted bool
+
+	// Without a terminal, the status is logged within a section from time to time
+	section   *logSection
+	heartbeat time.Time
+}
+
+// NewProgress starts showing a progress bar. A total of 0 shows the count and rate without a bar.
+func NewProgress(title string, total int64, options ...ProgressOption) *Progress {
+	p := &Progress{
+		title: title,
+		total: total,
+
+		start: time.Now(),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+
+		screen: &frame{plain: plainOutput()},
+
+		stopSignals: func() {},
+	}
+
+	for _, option := range options {
+		option(&p.options)
+	}
+
+	if p.screen.plain {
+		p.section = startSection(title)
+		p.heartbeat = p.start
+	} else {
+		p.signals, p.stopSignals = notifyInterrupt()
+		hideCursor()
+	}
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+
+		p.draw()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-p.signals:
+				p.interrupt()
+				return
+			case <-ticker.C:
+				p.draw()
+			}
+		}
+	}()
+
+	return p
+}
+
+// Add increases the current value by n
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current += n
+}
+
+// Set replaces the current value
+func (p *Progress) Set(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = n
+}
+
+// SetTotal replaces the total, e.g. once the size of a download is known
+func (p *Progress) SetTotal(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.total = n
+}
+
+// Title replaces the text in front of the bar
+func (p *Progress) Title(title string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.title = title
+}
+
+// Done stops updating and replaces the bar with a summary line
+func (p *Progress) Done() {
+	p.finish(nil)
+}
+
+// Fail is like Done, but shows err in the summary line, e.g. if the copy the bar tracks failed.
+// A warning (see Warning) or a skip (see Skip) is shown as such; a nil err is the same as Done.
+func (p *Progress) Fail(err error) {
+	p.finish(err)
+}
+
+func (p *Progress) finish(err error) {
+	select {
+	case <-p.stop:
+		return
+	default:
+		close(p.stop)
+	}
+
+	<-p.done
+
+	p.stopSignals()
+
+	if p.interrupted {
+		return
+	}
+
+	if p.section != nil {
+		p.section.end()
+	} else {
+		defer showCursor()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	elapsed := time.Since(p.start)
+
+	summary := p.format(p.current)
+	if p.total > 0 && p.current < p.total {
+		summary += " of " + p.format(p.total)
+	}
+	summary += " in " + formatDuration(elapsed)
+
+	if rate := p.rate(elapsed); rate > 0 {
+		summary += " · " + p.formatRate(rate)
+	}
+
+	p.screen.clear()
+	p.screen.line(resultLine(p.title, err, " "+summary))
+}
+
+// interrupt replaces the bar with a cancelled line and restores the cursor, then raises
+// Ctrl+C again, so it ends the process or reaches the handlers of the application
+func (p *Progress) interrupt() {
+	p.mu.Lock()
+	p.interrupted = true
+	p.screen.clear()
+	p.screen.line(resultLine(p.title, ErrUserAborted, ""))
+	p.mu.Unlock()
+
+	showCursor()
+
+	p.stopSignals()
+	raiseInterrupt()
+}
+
+// Reader returns a reader that adds the bytes read from r
+func (p *Progress) Reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, p: p}
+}
+
+// Writer returns a writer that adds the bytes written to w
+func (p *Progress) Writer(w io.Writer) io.Writer {
+	return &progressWriter{w: w, p: p}
+}
+
+type progressReader struct {
+	r io.Reader
+	p *Progress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.p.Add(int64(n))
+	return n, err
+}
+
+type progressWriter struct {
+	w io.Writer
+	p *Progress
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.p.Add(int64(n))
+	return n, err
+}
+
+// rate returns the average number of units per second
+func (p *Progress) rate(elapsed time.Duration) float64 {
+	if elapsed < time.Second/10 {
+		return 0
+	}
+	return float64(p.current) / elapsed.Seconds()
+}
+
+func (p *Progress) format(n int64) string {
+	if p.options.bytes {
+		return formatBytes(n)
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+func (p *Progress) formatRate(rate float64) string {
+	if p.options.bytes {
+		return formatBytes(int64(rate)) + "/s"
+	}
+	return strconv.FormatFloat(rate, 'f', 1, 64) + "/s"
+}
+
+// draw renders the bar with the current state
+func (p *Progress) draw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	elapsed := time.Since(p.start)
+	rate := p.rate(elapsed)
+
+	var info []string
+
+	if p.total > 0 {
+		info = append(info, fmt.Sprintf("%3d%%", min(p.current*100/p.total, 100)))
+		info = append(info, p.format(p.current)+"/"+p.format(p.total))
+	} else {
+		info = append(info, p.format(p.current))
+	}
+
+	if rate > 0 {
+		info = append(info, p.formatRate(rate))
+	}
+
+	info = append(info, formatDuration(elapsed))
+
+	if p.total > 0 && rate > 0 && p.current < p.total {
+		eta := time.Duration(float64(p.total-p.current) / rate * float64(time.Second))
+		info = append(info, "ETA "+formatDuration(eta))
+	}
+
+	text := themeMuted(strings.Join(info, " · "))
+	line := themeText(p.title) + " "
+
+	if p.screen.plain {
+		if time.Since(p.heartbeat) >= plainHeartbeat {
+			p.heartbeat = time.Now()
+			plainLine("  " + line + text)
+		}
+		return
+	}
+
+	if p.total > 0 {
+		width, _ := Size()
+
+		// The bar takes the space left next to the title and the usual length of the info, up to 40 columns.
+		// It doesn't depend on the info itself, so it doesn't jump while the numbers change.
+		barWidth := min(40, width-1-VisibleWidth(line)-50)
+		if barWidth >= 10 {
+			line += progressBar(float64(p.current)/float64(p.total), barWidth) + " "
+		}
+	}
+
+	p.screen.clear()
+	p.screen.inline(line + text)
+}
+
+// progressBar renders a bar of the given width, filled to fraction using partial blocks
+func progressBar(fraction float64, width int) string {
+	fraction = max(0, min(fraction, 1))
+
+	eighths := int(fraction * float64(width*8))
+	full, partial := eighths/8, eighths%8
+
+	bar := strings.Repeat(string(progressBlocks[7]), full)
+	if partial > 0 {
+		bar += string(progressBlocks[partial-1])
+	}
+
+	rest := width - full
+	if partial > 0 {
+		rest--
+	}
+
+	return themeAccent(bar) + themeSubtle(strings.Repeat("░", rest))
+}
+
+// formatDuration formats a duration for humans, e.g. "1.2s" or "1m05s"
+func formatDuration(d time.Duration) string {
+	if d < 10*time.Second {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+
+	d = d.Round(time.Second)
+
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
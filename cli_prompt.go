@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prompt asks for the fields of the struct v points to, picking the prompt by field type:
+// strings use Input, bools Confirm, numbers and durations a checked Input, and fields with
+// options Select, or MultiSelect for string slices. Fields that are already set are skipped,
+// so values from flags or config files are kept. Nested structs are prompted as well.
+//
+// Fields are configured with tags:
+//
+//	prompt:"Cluster name"      label, fields without it are not asked
+//	default:"dev"              value used if the input is left empty
+//	options:"a,b,c"            choices for Select or MultiSelect
+//	validate:"required,min=3"  rules: required, min and max (value for numbers, length otherwise)
+//	secret:"true"              hides the input like Password
+func Prompt(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("prompt target must be a pointer to a struct")
+	}
+
+	return promptStruct(rv.Elem())
+}
+
+func MustPrompt(v any) {
+	if err := Prompt(v); err != nil {
+		Fatal(err)
+	}
+}
+
+func promptStruct(rv reflect.Value) error {
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		value := rv.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		label, ok := field.Tag.Lookup("prompt")
+		if !ok {
+			if value.Kind() == reflect.Struct {
+				if err := promptStruct(value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if !value.IsZero() {
+			continue
+		}
+
+		if err := promptField(field, value, label); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// promptRules are the checks of a validate tag
+type promptRules struct {
+	required bool
+
+	min *float64
+	max *float64
+}
+
+func parsePromptRules(tag string) (promptRules, error) {
+	var rules promptRules
+
+	for _, rule := range splitTagList(tag) {
+		name, arg, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			rules.required = true
+
+		case "min", "max":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return rules, fmt.Errorf("invalid %s rule %q", name, rule)
+			}
+
+			if name == "min" {
+				rules.min = &n
+			} else {
+				rules.max = &n
+			}
+
+		default:
+			return rules, fmt.Errorf("unknown validate rule %q", rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// check validates a number, a length or a count against min and max
+func (r promptRules) check(n float64, what string) error {
+	if r.min != nil && n < *r.min {
+		return fmt.Errorf("%s must be at least %v", what, *r.min)
+	}
+	if r.max != nil && n > *r.max {
+		return fmt.Errorf("%s must be at most %v", what, *r.max)
+	}
+	return nil
+}
+
+// splitTagList splits a comma-separated tag value, dropping empty items
+func splitTagList(tag string) []string {
+	var items []string
+	for _, item := range strings.Split(tag, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// promptField asks for a single struct field and stores the answer
+func promptField(field reflect.StructField, value reflect.Value, label string) error {
+	rules, err := parsePromptRules(field.Tag.Get("validate"))
+	if err != nil {
+		return fmt.Errorf("field %s: %w", field.Name, err)
+	}
+
+	defaultValue := field.Tag.Get("default")
+	options := splitTagList(field.Tag.Get("options"))
+
+	secret := false
+	if tag, ok := field.Tag.Lookup("secret"); ok {
+		secret = tag == "" || tag == "true"
+	}
+
+	// number asks until the input parses and passes the rules; empty input keeps the zero value unless required
+	number := func(parse func(string) (float64, error)) (string, error) {
+		return input(promptContext{}, label, inputOptions{
+			placeholder: defaultValue,
+
+			validate: func(s string) error {
+				if s == "" {
+					if rules.required {
+						return errors.New("a value is required")
+					}
+					return nil
+				}
+
+				n, err := parse(s)
+				if err != nil {
+					return err
+				}
+
+				return rules.check(n, "value")
+			},
+		})
+	}
+
+	switch {
+	case value.Type() == durationType:
+		s, err := number(func(s string) (float64, error) {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return 0, errors.New("not a duration, e.g. 30s or 5m")
+			}
+			return d.Seconds(), nil
+		})
+		if err != nil || s == "" {
+			return err
+		}
+
+		d, _ := time.ParseDuration(s)
+		value.SetInt(int64(d))
+
+	case value.Kind() == reflect.Bool:
+		def, _ := strconv.ParseBool(defaultValue)
+
+		b, err := confirm(promptContext{}, label, def)
+		if err != nil {
+			return err
+		}
+
+		value.SetBool(b)
+
+	case value.Kind() == reflect.String && len(options) > 0:
+		selected := 0
+		for i, option := range options {
+			if option == defaultValue {
+				selected = i
+			}
+		}
+
+		index, err := selectItem(promptContext{}, label, options, selected)
+		if err != nil {
+			return err
+		}
+
+		value.SetString(options[index])
+
+	case value.Kind() == reflect.String:
+		s, err := input(promptContext{}, label, inputOptions{
+			placeholder: defaultValue,
+			mask:        secret,
+
+			validate: func(s string) error {
+				if s == "" {
+					if rules.required {
+						return errors.New("a value is required")
+					}
+					return nil
+				}
+				return rules.check(float64(len(graphemes(s))), "length")
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		value.SetString(s)
+
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String && len(options) > 0:
+		defaults := splitTagList(defaultValue)
+
+		selected := make([]bool, len(options))
+		for i, option := range options {
+			for _, d := range defaults {
+				selected[i] = selected[i] || option == d
+			}
+		}
+
+		indices, err := multiSelect(promptContext{}, label, options, selected, func(indices []int) error {
+			if len(indices) == 0 && rules.required {
+				return errors.New("select at least one item")
+			}
+			return rules.check(float64(len(indices)), "number of items")
+		})
+		if err != nil {
+			return err
+		}
+
+		slice := reflect.MakeSlice(value.Type(), len(indices), len(indices))
+		for i, index := range indices {
+			slice.Index(i).SetString(options[index])
+		}
+		value.Set(slice)
+
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+		s, err := input(promptContext{}, label, inputOptions{
+			placeholder: defaultValue,
+
+			validate: func(s string) error {
+				items := splitTagList(s)
+				if len(items) == 0 && rules.required {
+					return errors.New("a value is required")
+				}
+				return rules.check(float64(len(items)), "number of items")
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		items := splitTagList(s)
+
+		slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			slice.Index(i).SetString(item)
+		}
+		value.Set(slice)
+
+	case value.CanInt():
+		bits := value.Type().Bits()
+
+		s, err := number(func(s string) (float64, error) {
+			n, err := strconv.ParseInt(s, 10, bits)
+			if err != nil {
+				return 0, errors.New("not a whole number")
+			}
+			return float64(n), nil
+		})
+		if err != nil || s == "" {
+			return err
+		}
+
+		n, _ := strconv.ParseInt(s, 10, bits)
+		value.SetInt(n)
+
+	case value.CanUint():
+		bits := value.Type().Bits()
+
+		s, err := number(func(s string) (float64, error) {
+			n, err := strconv.ParseUint(s, 10, bits)
+			if err != nil {
+				return 0, errors.New("not a positive whole number")
+			}
+			return float64(n), nil
+		})
+		if err != nil || s == "" {
+			return err
+		}
+
+		n, _ := strconv.ParseUint(s, 10, bits)
+		value.SetUint(n)
+
+	case value.CanFloat():
+		bits := value.Type().Bits()
+
+		s, err := number(func(s string) (float64, error) {
+			n, err := strconv.ParseFloat(s, bits)
+			if err != nil {
+				return 0, errors.New("not a number")
+			}
+			return n, nil
+		})
+		if err != nil || s == "" {
+			return err
+		}
+
+		n, _ := strconv.ParseFloat(s, bits)
+		value.SetFloat(n)
+
+	default:
+		return fmt.Errorf("field %s: cannot prompt for type %s", field.Name, field.Type)
+	}
+
+	return nil
+}
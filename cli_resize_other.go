@@ -0,0 +1,40 @@
+//go:build !unix
+
+package cli
+
+import "time"
+
+// watchResize reports terminal size changes by polling, as there is no resize signal
+func watchResize() (<-chan struct{}, func()) {
+	resize := make(chan struct{}, 1)
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+
+		width, height := Size()
+
+		for {
+			select {
+			case <-ticker.C:
+				w, h := Size()
+				if w == width && h == height {
+					continue
+				}
+				width, height = w, h
+
+				select {
+				case resize <- struct{}{}:
+				default:
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return resize, func() {
+		close(done)
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+)
+
+// Symbols are the marks in front of finished tasks
+type Symbols struct {
+	Success string
+	Failure string
+	Warning string
+	Skipped string
+}
+
+// DefaultSymbols are the symbols used unless SetSymbols is called
+var DefaultSymbols = Symbols{
+	Success: "✓",
+	Failure: "✗",
+	Warning: "!",
+	Skipped: "⊘",
+}
+
+var currentSymbols = DefaultSymbols
+
+// SetSymbols replaces the marks of finished tasks; empty fields keep their default
+func SetSymbols(symbols Symbols) {
+	if symbols.Success == "" {
+		symbols.Success = DefaultSymbols.Success
+	}
+	if symbols.Failure == "" {
+		symbols.Failure = DefaultSymbols.Failure
+	}
+	if symbols.Warning == "" {
+		symbols.Warning = DefaultSymbols.Warning
+	}
+	if symbols.Skipped == "" {
+		symbols.Skipped = DefaultSymbols.Skipped
+	}
+
+	currentSymbols = symbols
+}
+
+// SkipError marks a task as skipped instead of failed
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return "skipped: " + e.Reason
+}
+
+// Skip is returned by a task that has nothing to do, e.g. Skip("no tests found")
+func Skip(reason string) error {
+	return &SkipError{Reason: reason}
+}
+
+// WarningError marks a task as finished with a warning instead of failed
+type WarningError struct {
+	Err error
+}
+
+func (e *WarningError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *WarningError) Unwrap() error {
+	return e.Err
+}
+
+// Warning is returned by a task that finished, but with a problem worth noting,
+// e.g. Warning(errors.New("3 files ignored")). It returns nil if err is nil.
+func Warning(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &WarningError{Err: err}
+}
+
+// outcomeKind is how a task finished
+type outcomeKind int
+
+const (
+	outcomeSuccess outcomeKind = iota
+	outcomeFailure
+	outcomeCancelled
+	outcomeWarning
+	outcomeSkipped
+)
+
+// outcome describes how a task finished, with its styled symbol and note
+type outcome struct {
+	kind   outcomeKind
+	symbol string
+	note   string
+}
+
+// classify returns the outcome of a task that returned err
+func classify(err error) outcome {
+	s := currentSymbols
+
+	var skip *SkipError
+	var warning *WarningError
+
+	switch {
+	case err == nil:
+		return outcome{outcomeSuccess, themeSuccess(s.Success), ""}
+
+	case errors.Is(err, errCleanupTimeout):
+		return outcome{outcomeCancelled, themeWarning(s.Failure), themeMuted("(cancelled, cleanup did not finish)")}
+
+	case errors.Is(err, ErrUserAborted):
+		return outcome{outcomeCancelled, themeWarning(s.Failure), themeMuted("(cancelled)")}
+
+	case errors.As(err, &skip):
+		return outcome{outcomeSkipped, themeMuted(s.Skipped), themeMuted("skipped: " + resultMessage(skip.Reason))}
+
+	case errors.As(err, &warning):
+		return outcome{outcomeWarning, themeWarning(s.Warning), themeWarning(resultMessage(warning.Error()))}
+
+	default:
+		return outcome{outcomeFailure, themeError(s.Failure), themeError(resultMessage(err.Error()))}
+	}
+}
+
+// isFailure reports whether err means a task failed; skipped tasks and warnings are not failures
+func isFailure(err error) bool {
+	switch classify(err).kind {
+	case outcomeFailure, outcomeCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// resultLine renders the final line of a task with the symbol and message for its outcome.
+// elapsed is shown after the title, e.g. " 1.2s", or empty.
+func resultLine(title string, err error, elapsed string) string {
+	o := classify(err)
+
+	line := o.symbol + " "
+	if o.kind == outcomeSkipped {
+		line += themeMuted(title)
+	} else {
+		line += themeText(title)
+	}
+	line += themeMuted(elapsed)
+
+	if o.note != "" {
+		line += " " + o.note
+	}
+
+	return line
+}
+
+// resultMessage keeps a message on a single line
+func resultMessage(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
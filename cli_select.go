@@ -8,34 +8,41 @@ import (
 )
 
 func Select(label string, items []string) (int, string, error) {
+	index, err := selectItem(promptContext{}, label, items, 0)
+
+	if err != nil {
+		return 0, "", err
+	}
+
+	return index, items[index], nil
+}
+
+// selectItem lets the user pick one of items, starting at index selected
+func selectItem(ctx promptContext, label string, items []string, selected int) (int, error) {
 	if len(items) == 0 {
-		return 0, "", errors.New("no items to select")
+		return 0, errors.New("no items to select")
 	}
 
 	var result int
 	var filter string
 
+	screen := ctx.frame()
+
 	err := withRawMode(func() error {
-		selectedIdx := 0
+		selectedIdx := min(max(selected, 0), len(items)-1)
 		filteredItems := items
 		filteredIndices := make([]int, len(items))
 		for i := range items {
 			filteredIndices[i] = i
 		}
-		lastLineCount := 0
+
+		keys := newKeyReader(os.Stdin)
+		defer keys.Close()
 
 		// Hide cursor during selection
 		fmt.Print(escHideCursor)
 		defer fmt.Print(escShowCursor)
 
-		clearPrevious := func() {
-			// Move up and clear each line
-			for i := 0; i < lastLineCount; i++ {
-				fmt.Print("\033[A")   // Move up
-				fmt.Print("\r\033[K") // Clear line
-			}
-		}
-
 		redraw := func() {
 			// Filter items first
 			if filter != "" {
@@ -61,57 +68,48 @@ func Select(label string, items []string) (int, string, error) {
 				}
 			}
 
-			lineCount := 0
-
 			// Print label
 			if label != "" {
-				fmt.Print("\r\033[K" + themeAccent(bold(label)) + "\r\n")
-				lineCount++
+				screen.line(themeAccent(bold(label)))
 			}
 
 			// Print filter line if active
 			if filter != "" {
-				fmt.Print("\r\033[K" + themeMuted("Filter: ") + themeText(filter) + "\r\n")
-				lineCount++
+				screen.line(themeMuted("Filter: ") + themeText(filter))
 			}
 
 			// Print options
 			for i, item := range filteredItems {
-				fmt.Print("\r\033[K")
 				if i == selectedIdx {
-					fmt.Print(themeSuccess("> ") + themeSuccess(item))
+					screen.line(themeSuccess("> ") + themeSuccess(item))
 				} else {
-					fmt.Print(themeSubtle("  ") + themeText(item))
+					screen.line(themeSubtle("  ") + themeText(item))
 				}
-				fmt.Print("\r\n")
-				lineCount++
 			}
-
-			lastLineCount = lineCount
 		}
 
 		// Initial draw
 		redraw()
 
 		for {
-			key, char, err := readKey(os.Stdin)
+			key, char, err := keys.Read()
 			if err != nil {
 				return err
 			}
 
 			switch key {
 			case keyCtrlC:
-				clearPrevious()
+				screen.clear()
 				return ErrUserAborted
 
 			case keyEnter:
 				if len(filteredItems) > 0 {
 					result = filteredIndices[selectedIdx]
-					clearPrevious()
+					screen.clear()
 					if label != "" {
-						fmt.Print("\r\033[K" + themeAccent(bold(label)) + "\r\n")
+						screen.line(themeAccent(bold(label)))
 					}
-					fmt.Print("\r\033[K" + themeSuccess("> ") + themeText(items[result]) + "\r\n")
+					screen.line(themeSuccess("> ") + themeText(items[result]))
 					return nil
 				}
 
@@ -126,30 +124,38 @@ func Select(label string, items []string) (int, string, error) {
 				}
 
 			case keyBackspace:
-				if len(filter) > 0 {
-					filter = filter[:len(filter)-1]
-				}
+				filter = trimLastGrapheme(filter)
 
 			case keyEscape:
+				if filter == "" && ctx.back {
+					screen.clear()
+					return errBack
+				}
 				filter = ""
 				selectedIdx = 0
 
+			case keyShiftTab:
+				if ctx.back {
+					screen.clear()
+					return errBack
+				}
+
 			default:
 				if char != 0 && char >= 32 {
 					filter += string(char)
 				}
 			}
 
-			clearPrevious()
+			screen.clear()
 			redraw()
 		}
 	})
 
 	if err != nil {
-		return 0, "", err
+		return 0, err
 	}
 
-	return result, items[result], nil
+	return result, nil
 }
 
 func MustSelect(label string, items []string) (int, string) {
@@ -161,3 +167,192 @@ func MustSelect(label string, items []string) (int, string) {
 
 	return index, value
 }
+
+// MultiSelect lets the user toggle any number of items with Space and confirm them with Enter
+func MultiSelect(label string, items []string) ([]int, []string, error) {
+	indices, err := multiSelect(promptContext{}, label, items, nil, nil)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make([]string, len(indices))
+	for i, index := range indices {
+		values[i] = items[index]
+	}
+
+	return indices, values, nil
+}
+
+// multiSelect returns the indices of the toggled items; selected marks the items toggled at the start.
+// validate, if set, can reject the toggled items with an error shown below the list.
+func multiSelect(ctx promptContext, label string, items []string, selected []bool, validate func([]int) error) ([]int, error) {
+	if len(items) == 0 {
+		return nil, errors.New("no items to select")
+	}
+
+	marked := make([]bool, len(items))
+	copy(marked, selected)
+
+	var result []int
+	var filter string
+
+	screen := ctx.frame()
+
+	err := withRawMode(func() error {
+		selectedIdx := 0
+		message := ""
+
+		var filteredIndices []int
+
+		keys := newKeyReader(os.Stdin)
+		defer keys.Close()
+
+		// Hide cursor during selection
+		fmt.Print(escHideCursor)
+		defer fmt.Print(escShowCursor)
+
+		redraw := func() {
+			filteredIndices = nil
+			for i, item := range items {
+				if filter == "" || strings.Contains(strings.ToLower(item), strings.ToLower(filter)) {
+					filteredIndices = append(filteredIndices, i)
+				}
+			}
+			selectedIdx = max(min(selectedIdx, len(filteredIndices)-1), 0)
+
+			// Print label
+			if label != "" {
+				screen.line(themeAccent(bold(label)) + " " + themeSubtle("(Space to toggle)"))
+			}
+
+			// Print filter line if active
+			if filter != "" {
+				screen.line(themeMuted("Filter: ") + themeText(filter))
+			}
+
+			// Print options
+			for i, index := range filteredIndices {
+				icon := themeMuted("○ ")
+				if marked[index] {
+					icon = themeSuccess("● ")
+				}
+
+				if i == selectedIdx {
+					screen.line(themeSuccess("> ") + icon + themeSuccess(items[index]))
+				} else {
+					screen.line(themeSubtle("  ") + icon + themeText(items[index]))
+				}
+			}
+
+			// Print error
+			if message != "" {
+				screen.line(themeError("  " + message))
+			}
+		}
+
+		// Initial draw
+		redraw()
+
+		for {
+			key, char, err := keys.Read()
+			if err != nil {
+				return err
+			}
+
+			if key != keyResize {
+				message = ""
+			}
+
+			switch key {
+			case keyCtrlC:
+				screen.clear()
+				return ErrUserAborted
+
+			case keyEnter:
+				result = nil
+				for i := range items {
+					if marked[i] {
+						result = append(result, i)
+					}
+				}
+
+				if validate != nil {
+					if err := validate(result); err != nil {
+						message = err.Error()
+						break
+					}
+				}
+
+				var values []string
+				for _, index := range result {
+					values = append(values, items[index])
+				}
+
+				screen.clear()
+				if label != "" {
+					screen.line(themeAccent(bold(label)))
+				}
+				screen.line(themeSuccess("> ") + themeText(strings.Join(values, ", ")))
+				return nil
+
+			case keySpace:
+				if len(filteredIndices) > 0 {
+					index := filteredIndices[selectedIdx]
+					marked[index] = !marked[index]
+				}
+
+			case keyUp:
+				if selectedIdx > 0 {
+					selectedIdx--
+				}
+
+			case keyDown:
+				if selectedIdx < len(filteredIndices)-1 {
+					selectedIdx++
+				}
+
+			case keyBackspace:
+				filter = trimLastGrapheme(filter)
+
+			case keyEscape:
+				if filter == "" && ctx.back {
+					screen.clear()
+					return errBack
+				}
+				filter = ""
+				selectedIdx = 0
+
+			case keyShiftTab:
+				if ctx.back {
+					screen.clear()
+					return errBack
+				}
+
+			default:
+				if char != 0 && char >= 32 {
+					filter += string(char)
+				}
+			}
+
+			screen.clear()
+			redraw()
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func MustMultiSelect(label string, items []string) ([]int, []string) {
+	indices, values, err := MultiSelect(label, items)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return indices, values
+}
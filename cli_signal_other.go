@@ -0,0 +1,10 @@
+//go:build !unix
+
+package cli
+
+import "os"
+
+// raiseInterrupt ends the process as Ctrl+C would, as a caught interrupt cannot be sent again
+func raiseInterrupt() {
+	os.Exit(130)
+}
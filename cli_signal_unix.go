@@ -0,0 +1,14 @@
+//go:build unix
+
+package cli
+
+import (
+	"os"
+	"syscall"
+)
+
+// raiseInterrupt sends Ctrl+C to the process again, once its own handler is stopped.
+// Without other handlers the process ends as if it was never caught.
+func raiseInterrupt() {
+	syscall.Kill(os.Getpid(), syscall.SIGINT)
+}
@@ -1,56 +1,283 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"sync"
+	"os"
+	"os/signal"
+	"strings"
 	"time"
 )
 
 // Spinner frames (braille dots)
 var spinnerFrames = []rune{'⠋', '⠙', '⠹', '⠸', '⠼', '⠴', '⠦', '⠧', '⠇', '⠏'}
 
-func Run(title string, fn func() error) error {
+// runGracePeriod is how long a cancelled task may take to clean up before RunContext returns without it
+const runGracePeriod = 5 * time.Second
+
+// RunOption configures Run, RunContext and RunTask
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	elapsed bool
+}
+
+// RunElapsed shows how long the task took next to its title once it finished
+func RunElapsed() RunOption {
+	return func(o *runOptions) {
+		o.elapsed = true
+	}
+}
+
+// Run shows a spinner while fn runs, followed by a line with the outcome: a failure with its
+// error, a warning (see Warning), a skipped task (see Skip) or a success.
+// As fn cannot be cancelled, Ctrl+C restores the cursor and is then handled as if Run
+// did not catch it.
+func Run(title string, fn func() error, options ...RunOption) error {
+	return runTask(context.Background(), title, func(context.Context, *Task) error {
+		return fn()
+	}, false, options)
+}
+
+// RunContext shows a spinner while fn runs. Ctrl+C cancels the context passed to fn and
+// waits for fn to clean up; a second Ctrl+C, or fn not returning within a grace period,
+// stops waiting. It then returns ErrUserAborted. The cursor is restored in any case.
+// If stdout is not a terminal, plain log lines are written instead of a spinner, in a
+// collapsible section on GitHub Actions and GitLab CI.
+func RunContext(ctx context.Context, title string, fn func(ctx context.Context) error, options ...RunOption) error {
+	return RunTask(ctx, title, func(ctx context.Context, _ *Task) error {
+		return fn(ctx)
+	}, options...)
+}
+
+// RunTask is like RunContext, but fn can report its status through task:
+// change the title, show a detail line and a percentage, or log messages above the spinner.
+func RunTask(ctx context.Context, title string, fn func(ctx context.Context, task *Task) error, options ...RunOption) error {
+	return runTask(ctx, title, fn, true, options)
+}
+
+func runTask(ctx context.Context, title string, fn func(ctx context.Context, task *Task) error, cancellable bool, options []RunOption) error {
+	o := &runOptions{}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	task := newTask(title)
+	start := time.Now()
+
+	err := spin(ctx, task, fn, cancellable)
+
+	elapsed := ""
+	if o.elapsed {
+		elapsed = " " + formatDuration(time.Since(start))
+	}
+
+	title, _, _ = task.status()
+
+	screen := &frame{plain: plainOutput()}
+	screen.line(resultLine(title, err, elapsed))
+
+	if errors.Is(err, ErrUserAborted) {
+		return ErrUserAborted
+	}
+
+	return err
+}
+
+// errCleanupTimeout is returned by spin if a cancelled task did not return within the grace period
+var errCleanupTimeout = fmt.Errorf("%w: cleanup did not finish", ErrUserAborted)
+
+// spin shows a spinner with the status of task while fn runs and removes it again.
+// It returns the error of fn, or ErrUserAborted if the user cancelled the task.
+// If fn is not cancellable, Ctrl+C is raised again once the terminal is restored.
+func spin(ctx context.Context, task *Task, fn func(ctx context.Context, task *Task) error, cancellable bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Ctrl+C cancels the task instead of killing the process
+	signals, stop := notifyInterrupt()
+	defer stop()
+
 	var fnErr error
-	var wg sync.WaitGroup
+	var fnPanic any
+
+	// Without a terminal, the task is reported as log lines instead of a spinner
+	var plain *plainTask
+	if plainOutput() {
+		plain = newPlainTask(task)
+	}
+
 	done := make(chan struct{})
 
 	// Start the action in a goroutine
-	wg.Add(1)
 	go func() {
-		defer wg.Done()
-		fnErr = fn()
-		close(done)
+		defer close(done)
+
+		// A panic is raised again by the caller, once the terminal is restored
+		defer func() {
+			fnPanic = recover()
+		}()
+
+		fnErr = fn(ctx, task)
 	}()
 
 	// Hide cursor
-	hideCursor()
-	defer showCursor()
+	if plain == nil {
+		hideCursor()
+		defer showCursor()
+	}
 
 	// Spinner loop
-	frame := 0
+	screen := &frame{}
+	index := 0
 	ticker := time.NewTicker(80 * time.Millisecond)
 	defer ticker.Stop()
 
+	var interrupted bool
+	var grace <-chan time.Time
+
+	// flush prints the logged messages above the spinner
+	flush := func() {
+		for _, log := range task.takeLogs() {
+			fmt.Print("\r\033[K" + themeText(strings.ReplaceAll(log, "\n", "\r\n")) + "\r\n")
+		}
+	}
+
+	// finish removes the spinner and prints the remaining messages
+	finish := func() {
+		if plain != nil {
+			plain.finish()
+			return
+		}
+
+		screen.clear()
+		flush()
+	}
+
 	for {
 		select {
 		case <-done:
-			clearLine()
-			fmt.Println(themeSuccess("✓") + " " + themeText(title))
-			wg.Wait()
+			finish()
+
+			if fnPanic != nil {
+				panic(fnPanic)
+			}
+
+			if interrupted {
+				return ErrUserAborted
+			}
+
 			return fnErr
+
+		case <-signals:
+			if !cancellable {
+				finish()
+
+				if plain == nil {
+					showCursor()
+				}
+
+				title, _, _ := task.status()
+
+				screen := &frame{plain: plainOutput()}
+				screen.line(resultLine(title, ErrUserAborted, ""))
+
+				// fn cannot be cancelled, so the interrupt is handled as if it was never caught:
+				// it ends the process, or reaches the handlers of the application
+				stop()
+				raiseInterrupt()
+
+				<-done
+				return ErrUserAborted
+			}
+
+			if interrupted {
+				// A second Ctrl+C stops waiting for the task
+				grace = time.After(0)
+				break
+			}
+
+			interrupted = true
+			grace = time.After(runGracePeriod)
+			cancel()
+
+		case <-grace:
+			finish()
+
+			return errCleanupTimeout
+
 		case <-ticker.C:
-			clearLine()
-			spinner := themeHighlight(string(spinnerFrames[frame]))
-			fmt.Print(spinner + " " + themeText(title))
-			frame = (frame + 1) % len(spinnerFrames)
+			if plain != nil {
+				plain.update(interrupted)
+				break
+			}
+
+			screen.clear()
+			flush()
+
+			title, suffix, detail := task.status()
+			if interrupted {
+				suffix += " cancelling…"
+			}
+
+			spinner := themeHighlight(string(spinnerFrames[index]))
+			lines := []string{spinner + " " + themeText(title) + themeMuted(suffix)}
+
+			if detail != "" {
+				lines = append(lines, "  "+themeMuted(detail))
+			}
+			for _, line := range task.tailLines() {
+				lines = append(lines, "  "+themeMuted("│ "+line))
+			}
+
+			// The last line stays open, so the frame doesn't end with an empty row
+			for _, line := range lines[:len(lines)-1] {
+				screen.line(line)
+			}
+			screen.inline(lines[len(lines)-1])
+
+			index = (index + 1) % len(spinnerFrames)
 		}
 	}
 }
 
-func MustRun(title string, fn func() error) error {
-	err := Run(title, fn)
+// notifyInterrupt catches Ctrl+C until stop is called. Termination requests are left alone,
+// so the process still ends when it is killed.
+func notifyInterrupt() (<-chan os.Signal, func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+
+	return signals, func() {
+		signal.Stop(signals)
+	}
+}
+
+func MustRun(title string, fn func() error, options ...RunOption) error {
+	err := Run(title, fn, options...)
+
+	if isFailure(err) {
+		Fatal(err)
+	}
+
+	return err
+}
+
+func MustRunContext(ctx context.Context, title string, fn func(ctx context.Context) error, options ...RunOption) error {
+	err := RunContext(ctx, title, fn, options...)
+
+	if isFailure(err) {
+		Fatal(err)
+	}
+
+	return err
+}
+
+func MustRunTask(ctx context.Context, title string, fn func(ctx context.Context, task *Task) error, options ...RunOption) error {
+	err := RunTask(ctx, title, fn, options...)
 
-	if err != nil {
+	if isFailure(err) {
 		Fatal(err)
 	}
 
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StepsOption configures Steps
+type StepsOption func(*stepsOptions)
+
+type stepsOptions struct {
+	continueOnError bool
+}
+
+// StepsContinueOnError runs the remaining steps after a step failed, instead of stopping
+func StepsContinueOnError() StepsOption {
+	return func(o *stepsOptions) {
+		o.continueOnError = true
+	}
+}
+
+// stepResult is the outcome of a single step
+type stepResult struct {
+	title    string
+	err      error
+	duration time.Duration
+	ran      bool
+}
+
+// Steps runs steps one after another with a spinner each and prints a summary with the
+// outcome and duration of every step. By default it stops at the first failed step.
+// It returns a *GroupError listing the failed steps; skipped steps and warnings are not failures.
+func Steps(ctx context.Context, steps []Job, options ...StepsOption) error {
+	o := &stepsOptions{}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	results := make([]stepResult, len(steps))
+	for i, step := range steps {
+		results[i].title = step.Title
+	}
+
+	start := time.Now()
+	aborted := false
+
+	for i, step := range steps {
+		task := newTask(step.Title)
+		begin := time.Now()
+
+		err := spin(ctx, task, step.Run, true)
+
+		title, _, _ := task.status()
+		results[i] = stepResult{title: title, err: err, duration: time.Since(begin), ran: true}
+
+		screen := &frame{plain: plainOutput()}
+		screen.line(stepLine(results[i]))
+
+		if errors.Is(err, ErrUserAborted) {
+			aborted = true
+			break
+		}
+
+		if isFailure(err) && !o.continueOnError {
+			break
+		}
+	}
+
+	printStepsSummary(results, time.Since(start))
+
+	if aborted {
+		return ErrUserAborted
+	}
+
+	result := &GroupError{Total: len(steps)}
+	for _, r := range results {
+		if isFailure(r.err) {
+			result.Failed = append(result.Failed, TaskError{Title: r.title, Err: r.err})
+		}
+	}
+
+	if len(result.Failed) == 0 {
+		return nil
+	}
+	return result
+}
+
+// stepLine renders the outcome of a step
+func stepLine(r stepResult) string {
+	if !r.ran {
+		return themeMuted("○ " + r.title + " (not run)")
+	}
+
+	return resultLine(r.title, r.err, " "+formatDuration(r.duration))
+}
+
+// printStepsSummary prints all steps with aligned durations, followed by the totals
+func printStepsSummary(results []stepResult, total time.Duration) {
+	titleWidth := 0
+	for _, r := range results {
+		titleWidth = max(titleWidth, VisibleWidth(r.title))
+	}
+
+	var succeeded, failed, warnings, skipped, notRun int
+
+	screen := &frame{plain: plainOutput()}
+	screen.line("")
+	screen.line(themeAccent(bold("Summary")))
+
+	for _, r := range results {
+		icon, note, duration := themeMuted("○"), themeMuted("not run"), ""
+
+		if r.ran {
+			o := classify(r.err)
+			icon, note, duration = o.symbol, o.note, formatDuration(r.duration)
+
+			switch o.kind {
+			case outcomeSuccess:
+				succeeded++
+			case outcomeFailure, outcomeCancelled:
+				failed++
+			case outcomeWarning:
+				warnings++
+			case outcomeSkipped:
+				skipped++
+			}
+		} else {
+			notRun++
+		}
+
+		line := "  " + icon + " " + padRight(themeText(r.title), titleWidth) + "  "
+		if note != "" {
+			line += themeMuted(padRight(duration, 7)) + " " + note
+		} else {
+			line += themeMuted(duration)
+		}
+		screen.line(line)
+	}
+
+	counts := []string{formatDuration(total) + " total", strconv.Itoa(succeeded) + " succeeded"}
+	if failed > 0 {
+		counts = append(counts, strconv.Itoa(failed)+" failed")
+	}
+	if warnings > 0 {
+		counts = append(counts, strconv.Itoa(warnings)+" with warnings")
+	}
+	if skipped > 0 {
+		counts = append(counts, strconv.Itoa(skipped)+" skipped")
+	}
+	if notRun > 0 {
+		counts = append(counts, strconv.Itoa(notRun)+" not run")
+	}
+
+	screen.line("  " + themeMuted(strings.Join(counts, " · ")))
+}
+
+func MustSteps(ctx context.Context, steps []Job, options ...StepsOption) error {
+	err := Steps(ctx, steps, options...)
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return err
+}
@@ -3,7 +3,6 @@ package cli
 import (
 	"fmt"
 	"strings"
-	"unicode/utf8"
 )
 
 // Box drawing characters
@@ -22,14 +21,21 @@ const (
 )
 
 func Table(headers []string, rows [][]string) {
+	for _, line := range tableLines(headers, rows) {
+		fmt.Println(line)
+	}
+}
+
+// tableLines renders a table into lines
+func tableLines(headers []string, rows [][]string) []string {
 	if len(headers) == 0 {
-		return
+		return nil
 	}
 
 	// Calculate column widths
 	colWidths := make([]int, len(headers))
 	for i, h := range headers {
-		colWidths[i] = utf8.RuneCountInString(h)
+		colWidths[i] = VisibleWidth(h)
 	}
 	for _, row := range rows {
 		for i, cell := range row {
@@ -37,7 +43,7 @@ func Table(headers []string, rows [][]string) {
 				// Sanitize: replace newlines for width calculation
 				sanitized := strings.ReplaceAll(cell, "\n", " ")
 				sanitized = strings.ReplaceAll(sanitized, "\r", "")
-				w := utf8.RuneCountInString(sanitized)
+				w := VisibleWidth(sanitized)
 				if w > colWidths[i] {
 					colWidths[i] = w
 				}
@@ -45,6 +51,30 @@ func Table(headers []string, rows [][]string) {
 		}
 	}
 
+	// Shrink the widest columns until the table fits the terminal
+	if IsTerminal() {
+		width, _ := Size()
+
+		total := len(colWidths) + 1
+		for _, w := range colWidths {
+			total += w + 2
+		}
+
+		for total > width {
+			widest := 0
+			for i, w := range colWidths {
+				if w > colWidths[widest] {
+					widest = i
+				}
+			}
+			if colWidths[widest] <= 3 {
+				break
+			}
+			colWidths[widest]--
+			total--
+		}
+	}
+
 	// Add padding
 	for i := range colWidths {
 		colWidths[i] += 2
@@ -79,9 +109,9 @@ func Table(headers []string, rows [][]string) {
 				cell = strings.ReplaceAll(cells[i], "\n", " ")
 				cell = strings.ReplaceAll(cell, "\r", "")
 			}
+			cell = truncate(cell, w-2)
 			// Calculate padding based on visible width (before styling)
-			visibleWidth := utf8.RuneCountInString(cell)
-			padding := w - visibleWidth - 1
+			padding := w - VisibleWidth(cell) - 1
 			if padding < 0 {
 				padding = 0
 			}
@@ -99,12 +129,15 @@ func Table(headers []string, rows [][]string) {
 		return sb.String()
 	}
 
-	// Print table
-	fmt.Println(themeMuted(topLine))
-	fmt.Println(buildRow(headers, true))
-	fmt.Println(themeMuted(midLine))
+	lines := []string{
+		themeMuted(topLine),
+		buildRow(headers, true),
+		themeMuted(midLine),
+	}
 	for _, row := range rows {
-		fmt.Println(buildRow(row, false))
+		lines = append(lines, buildRow(row, false))
 	}
-	fmt.Println(themeMuted(bottomLine))
+	lines = append(lines, themeMuted(bottomLine))
+
+	return lines
 }
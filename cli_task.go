@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// Job is a unit of work with a title, run by Steps one after another or by RunGroup concurrently
+type Job struct {
+	Title string
+	Run   func(ctx context.Context, task *Task) error
+}
+
+// Task reports the status of a running task to its spinner. It is safe for concurrent use.
+type Task struct {
+	mu sync.Mutex
+
+	title   string
+	detail  string
+	percent float64
+
+	// logs are printed above the spinner on its next frame
+	logs []string
+
+	// tail are the last lines of output shown below the spinner, used by Exec
+	tail []string
+}
+
+func newTask(title string) *Task {
+	return &Task{
+		title:   title,
+		percent: -1,
+	}
+}
+
+// Title replaces the text next to the spinner
+func (t *Task) Title(title string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.title = title
+}
+
+// Detail shows a dim line below the spinner, e.g. "uploading layer 3/12"; an empty string removes it
+func (t *Task) Detail(detail string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.detail = detail
+}
+
+// Percent shows how much of the task is done, from 0 to 100; a negative value removes it
+func (t *Task) Percent(percent float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.percent = min(percent, 100)
+}
+
+// Log prints a message above the spinner
+func (t *Task) Log(v ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.logs = append(t.logs, fmt.Sprint(v...))
+}
+
+func (t *Task) Logf(format string, a ...any) {
+	t.Log(fmt.Sprintf(format, a...))
+}
+
+// status returns the current title and the rendered suffix and detail line
+func (t *Task) status() (title, suffix, detail string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.percent >= 0 {
+		suffix = " " + strconv.Itoa(int(t.percent)) + "%"
+	}
+
+	return t.title, suffix, t.detail
+}
+
+// takeLogs returns the messages logged since the last call
+func (t *Task) takeLogs() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	logs := t.logs
+	t.logs = nil
+
+	return logs
+}
+
+func (t *Task) setTail(lines []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tail = lines
+}
+
+func (t *Task) tailLines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.tail
+}
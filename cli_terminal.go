@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+// ellipsis marks text that was cut to fit the terminal width
+const ellipsis = "…"
+
+// Size returns the width and height of the terminal in columns and rows.
+// If stdout is not a terminal, $COLUMNS and $LINES are used, defaulting to 80x24.
+func Size() (width, height int) {
+	if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 && h > 0 {
+		return w, h
+	}
+
+	width, height = 80, 24
+
+	if v, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && v > 0 {
+		width = v
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("LINES")); err == nil && v > 0 {
+		height = v
+	}
+
+	return width, height
+}
+
+// rowCount returns the number of terminal rows a line of the given width occupies
+func rowCount(lineWidth, termWidth int) int {
+	if lineWidth <= 0 || termWidth <= 0 {
+		return 1
+	}
+	return (lineWidth + termWidth - 1) / termWidth
+}
+
+// frame renders the lines of an interactive widget and clears them again before the next redraw.
+// Lines are cut to the terminal width; if the terminal shrinks afterwards, the rows the
+// terminal wrapped them into are cleared as well.
+type frame struct {
+	widths []int
+	open   bool
+
+	// plain writes lines as a log, without escape sequences, and never clears them
+	plain bool
+}
+
+// line writes s as a complete line
+func (f *frame) line(s string) {
+	if f.plain {
+		plainLine(s)
+		return
+	}
+
+	width, _ := Size()
+	s = truncate(s, width)
+
+	fmt.Print("\r\033[K" + s + "\r\n")
+	f.widths = append(f.widths, VisibleWidth(s))
+}
+
+// inline writes s as the last line without a line break, keeping the cursor behind it
+func (f *frame) inline(s string) {
+	if f.plain {
+		plainLine(s)
+		return
+	}
+
+	width, _ := Size()
+	s = truncate(s, width-1)
+
+	fmt.Print("\r\033[K" + s)
+	f.widths = append(f.widths, VisibleWidth(s))
+	f.open = true
+}
+
+// clear removes all lines written since the last clear
+func (f *frame) clear() {
+	width, _ := Size()
+
+	rows := 0
+	for _, w := range f.widths {
+		rows += rowCount(w, width)
+	}
+
+	if f.open {
+		// The cursor is still on the last row of the unterminated line
+		fmt.Print("\r\033[K")
+		rows--
+	}
+
+	for i := 0; i < rows; i++ {
+		fmt.Print("\033[A")   // Move up
+		fmt.Print("\r\033[K") // Clear line
+	}
+
+	f.widths = f.widths[:0]
+	f.open = false
+}
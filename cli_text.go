@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"unicode/utf8"
 )
 
 func Text(label, placeholder string) (string, error) {
@@ -16,69 +15,58 @@ func Text(label, placeholder string) (string, error) {
 			lines = strings.Split(placeholder, "\n")
 		}
 		currentLine := len(lines) - 1
-		lastLineCount := 0
+		screen := &frame{}
+
+		keys := newKeyReader(os.Stdin)
+		defer keys.Close()
 
 		// Hide cursor during editing
 		fmt.Print(escHideCursor)
 		defer fmt.Print(escShowCursor)
 
-		clearPrevious := func() {
-			for i := 0; i < lastLineCount; i++ {
-				fmt.Print("\033[A")   // Move up
-				fmt.Print("\r\033[K") // Clear line
-			}
-		}
-
 		redraw := func() {
-			lineCount := 0
+			width, _ := Size()
 
 			// Print label and hint
 			if label != "" {
-				fmt.Print("\r\033[K" + themeAccent(bold(label)) + " " + themeSubtle("(Ctrl+D to submit)") + "\r\n")
-				lineCount++
+				screen.line(themeAccent(bold(label)) + " " + themeSubtle("(Ctrl+D to submit)"))
 			}
 
 			// Print lines
 			for i, line := range lines {
 				lineNum := themeMuted(fmt.Sprintf("%2d │ ", i+1))
-				fmt.Print("\r\033[K")
 				if i == currentLine {
-					fmt.Print(lineNum + themeText(line) + themeSubtle("█"))
+					// Keep the end of the current line visible, the cursor sits behind it
+					text := truncateLeft(line, width-1-VisibleWidth(lineNum))
+					screen.line(lineNum + themeText(text) + themeSubtle("█"))
 				} else {
-					fmt.Print(lineNum + themeText(line))
+					screen.line(lineNum + themeText(line))
 				}
-				fmt.Print("\r\n")
-				lineCount++
 			}
-
-			lastLineCount = lineCount
 		}
 
 		redraw()
 
 		for {
-			key, char, err := readKey(os.Stdin)
+			key, char, err := keys.Read()
 			if err != nil {
 				return err
 			}
 
 			switch key {
 			case keyCtrlC:
-				clearPrevious()
+				screen.clear()
 				return ErrUserAborted
 
 			case keyCtrlD:
 				// Ctrl+D submits the text
 				result = strings.Join(lines, "\n")
-				clearPrevious()
+				screen.clear()
 				if label != "" {
-					fmt.Print("\r\033[K" + themeAccent(bold(label)) + "\r\n")
-				}
-				preview := strings.Join(lines, " ")
-				if len(preview) > 60 {
-					preview = preview[:57] + "..."
+					screen.line(themeAccent(bold(label)))
 				}
-				fmt.Print("\r\033[K" + themeSuccess("> ") + themeText(preview) + "\r\n")
+				preview := truncate(strings.Join(lines, " "), 60)
+				screen.line(themeSuccess("> ") + themeText(preview))
 				return nil
 
 			case keyEnter:
@@ -88,8 +76,7 @@ func Text(label, placeholder string) (string, error) {
 
 			case keyBackspace:
 				if len(lines[currentLine]) > 0 {
-					_, size := utf8.DecodeLastRuneInString(lines[currentLine])
-					lines[currentLine] = lines[currentLine][:len(lines[currentLine])-size]
+					lines[currentLine] = trimLastGrapheme(lines[currentLine])
 				} else if currentLine > 0 {
 					// Join with previous line
 					lines = append(lines[:currentLine], lines[currentLine+1:]...)
@@ -112,7 +99,7 @@ func Text(label, placeholder string) (string, error) {
 				}
 			}
 
-			clearPrevious()
+			screen.clear()
 			redraw()
 		}
 	})
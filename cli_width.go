@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// runeRange is an inclusive range of code points
+type runeRange struct {
+	lo, hi rune
+}
+
+// wideRunes lists the East Asian Wide and Fullwidth ranges,
+// including emoji that are presented as wide by default
+var wideRunes = []runeRange{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+	{0x23F0, 0x23F0}, {0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+	{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+	{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4},
+	{0x17000, 0x18AFF}, {0x1B000, 0x1B16F}, {0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F251}, {0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF}, {0x1F7E0, 0x1F7EB}, {0x1F90C, 0x1F9FF}, {0x1FA70, 0x1FAFF},
+	{0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// Code points with special meaning for grapheme clusters
+const (
+	runeZWJ  = 0x200D // zero width joiner
+	runeVS15 = 0xFE0E // text presentation selector
+	runeVS16 = 0xFE0F // emoji presentation selector
+)
+
+func inRanges(r rune, ranges []runeRange) bool {
+	lo, hi := 0, len(ranges)-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < ranges[mid].lo:
+			hi = mid - 1
+		case r > ranges[mid].hi:
+			lo = mid + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// isRegionalIndicator reports whether r is one half of a flag emoji
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isExtending reports whether r attaches to the preceding grapheme cluster
+func isExtending(r rune) bool {
+	switch {
+	case r == runeZWJ:
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F, r >= 0xE0100 && r <= 0xE01EF: // variation selectors
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF: // emoji skin tone modifiers
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // emoji tag sequences
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc)
+}
+
+// runeWidth returns the number of columns a single code point occupies
+func runeWidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case r < 32 || (r >= 0x7F && r < 0xA0):
+		return 0
+	case r >= 0x1160 && r <= 0x11FF: // Hangul medial vowels and final consonants
+		return 0
+	case isExtending(r), unicode.Is(unicode.Cf, r):
+		return 0
+	case inRanges(r, wideRunes):
+		return 2
+	}
+	return 1
+}
+
+// graphemeLen returns the byte length of the grapheme cluster at the start of s
+func graphemeLen(s string) int {
+	if s == "" {
+		return 0
+	}
+
+	first, n := utf8.DecodeRuneInString(s)
+
+	// CR LF is a single cluster
+	if first == '\r' && len(s) > 1 && s[1] == '\n' {
+		return 2
+	}
+
+	// Flags are made of two regional indicators
+	if isRegionalIndicator(first) {
+		if r, size := utf8.DecodeRuneInString(s[n:]); isRegionalIndicator(r) {
+			n += size
+		}
+	}
+
+	prev := first
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !isExtending(r) && prev != runeZWJ {
+			break
+		}
+		prev = r
+		n += size
+	}
+
+	return n
+}
+
+// graphemeWidth returns the number of columns a single grapheme cluster occupies
+func graphemeWidth(g string) int {
+	first, size := utf8.DecodeRuneInString(g)
+
+	if isRegionalIndicator(first) && size < len(g) {
+		return 2
+	}
+
+	width := runeWidth(first)
+
+	for _, r := range g[size:] {
+		switch r {
+		case runeVS16:
+			if width > 0 {
+				width = 2
+			}
+		case runeVS15:
+			if width > 0 {
+				width = 1
+			}
+		}
+	}
+
+	return width
+}
+
+// stringWidth returns the number of columns plain text s occupies
+func stringWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		n := graphemeLen(s[i:])
+		width += graphemeWidth(s[i : i+n])
+		i += n
+	}
+	return width
+}
+
+// graphemes splits s into its grapheme clusters
+func graphemes(s string) []string {
+	var result []string
+	for i := 0; i < len(s); {
+		n := graphemeLen(s[i:])
+		result = append(result, s[i:i+n])
+		i += n
+	}
+	return result
+}
+
+// trimLastGrapheme removes the last grapheme cluster from s, as Backspace does
+func trimLastGrapheme(s string) string {
+	last := 0
+	for i := 0; i < len(s); {
+		last = i
+		i += graphemeLen(s[i:])
+	}
+	return s[:last]
+}
@@ -0,0 +1,50 @@
+package cli
+
+import "testing"
+
+func TestGraphemeLen(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{s: "", want: 0},
+		{s: "ab", want: 1},
+		{s: "\r\n", want: 2},
+		{s: "日本", want: 3},
+		{s: "e\u0301x", want: 3},   // e + combining acute accent
+		{s: "❤\ufe0fx", want: 6},   // heart + emoji presentation selector
+		{s: "🇨🇭x", want: 8},        // flag of two regional indicators
+		{s: "👍🏽x", want: 8},        // thumbs up + skin tone
+		{s: "👩\u200d💻x", want: 11}, // woman + ZWJ + laptop
+		{s: "🇨🇭🇩🇪", want: 8},       // two flags
+	}
+
+	for _, tt := range tests {
+		if got := graphemeLen(tt.s); got != tt.want {
+			t.Errorf("graphemeLen(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGraphemeWidth(t *testing.T) {
+	tests := []struct {
+		g    string
+		want int
+	}{
+		{g: "a", want: 1},
+		{g: "日", want: 2},
+		{g: "e\u0301", want: 1},
+		{g: "❤", want: 1},
+		{g: "❤\ufe0f", want: 2},
+		{g: "⌚\ufe0e", want: 1}, // text presentation selector
+		{g: "🇨🇭", want: 2},
+		{g: "👩\u200d💻", want: 2},
+		{g: "\t", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := graphemeWidth(tt.g); got != tt.want {
+			t.Errorf("graphemeWidth(%q) = %d, want %d", tt.g, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+// Wizard asks a series of steps that may depend on earlier answers, e.g. follow-up
+// questions for the chosen cloud provider. Esc or Shift+Tab returns to the previous step.
+type Wizard struct {
+	// Title is shown above the steps
+	Title string
+
+	Steps []WizardStep
+
+	// StatePath is a file the answers are saved to if the user aborts with Ctrl+C.
+	// The next Run offers to resume from there; the file is removed once the wizard completes.
+	StatePath string
+}
+
+// WizardStep is a question of a Wizard
+type WizardStep struct {
+	Field FormField
+
+	// When decides whether the step is asked, based on the answers so far; nil always asks it
+	When func(Answers) bool
+
+	// DependsOn names earlier steps; if one of their answers changes, this step is asked again from its default
+	DependsOn []string
+}
+
+// wizardState is the content of the state file
+type wizardState struct {
+	Answers Answers `json:"answers"`
+}
+
+// active reports whether step i is asked with the given answers
+func (w *Wizard) active(i int, answers Answers) bool {
+	when := w.Steps[i].When
+	return when == nil || when(answers)
+}
+
+// resetDependents drops the answers of all steps depending on name, directly or through other steps
+func (w *Wizard) resetDependents(name string, answers Answers) {
+	for _, step := range w.Steps {
+		for _, dep := range step.DependsOn {
+			if dep == name {
+				if _, ok := answers[step.Field.name]; ok {
+					delete(answers, step.Field.name)
+					w.resetDependents(step.Field.name, answers)
+				}
+			}
+		}
+	}
+}
+
+// validate checks that steps have unique names and only depend on earlier steps
+func (w *Wizard) validate() error {
+	if len(w.Steps) == 0 {
+		return errors.New("no steps to ask")
+	}
+
+	seen := map[string]bool{}
+
+	for _, step := range w.Steps {
+		for _, dep := range step.DependsOn {
+			if !seen[dep] {
+				return fmt.Errorf("step %q depends on %q, which is not an earlier step", step.Field.name, dep)
+			}
+		}
+
+		if seen[step.Field.name] {
+			return fmt.Errorf("duplicate step %q", step.Field.name)
+		}
+		seen[step.Field.name] = true
+	}
+
+	return nil
+}
+
+// loadState returns the answers saved by an aborted run, keeping only those that fit the steps
+func (w *Wizard) loadState() Answers {
+	data, err := os.ReadFile(w.StatePath)
+	if err != nil {
+		return nil
+	}
+
+	var state wizardState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil
+	}
+
+	answers := Answers{}
+	for _, step := range w.Steps {
+		value, ok := state.Answers[step.Field.name]
+		if ok && reflect.TypeOf(value) == reflect.TypeOf(step.Field.value) {
+			answers[step.Field.name] = value
+		}
+	}
+
+	if len(answers) == 0 {
+		return nil
+	}
+	return answers
+}
+
+func (w *Wizard) saveState(answers Answers) error {
+	data, err := json.MarshalIndent(wizardState{Answers: answers}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(w.StatePath, data, 0600)
+}
+
+// Run asks the steps and returns the answers of the steps that apply
+func (w *Wizard) Run() (Answers, error) {
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+
+	answers := Answers{}
+
+	if w.StatePath != "" {
+		if saved := w.loadState(); saved != nil {
+			resume, err := Confirm("Resume where you left off?", true)
+			if err != nil {
+				return nil, err
+			}
+
+			if resume {
+				answers = saved
+			}
+		}
+	}
+
+	header := &frame{}
+	screen := &frame{}
+
+	// render shows the title, the answered steps and the progress above the current step
+	render := func(pos int) {
+		if w.Title != "" {
+			header.line(themeAccent(bold(w.Title)))
+		}
+
+		number, total := 0, 0
+		for i, step := range w.Steps {
+			if !w.active(i, answers) {
+				continue
+			}
+
+			total++
+			if i <= pos {
+				number++
+			}
+
+			if i < pos {
+				value := step.Field.format(answers[step.Field.name])
+				header.line(themeSuccess("✓ ") + themeMuted(step.Field.label+": ") + themeText(value))
+			}
+		}
+
+		if pos < len(w.Steps) {
+			header.line(themeMuted("Step " + strconv.Itoa(number) + " of " + strconv.Itoa(total)))
+		}
+	}
+
+	// next returns the first step after pos that applies, or len(Steps) if there is none
+	next := func(pos int) int {
+		for pos++; pos < len(w.Steps) && !w.active(pos, answers); pos++ {
+		}
+		return pos
+	}
+
+	// previous returns the last step before pos that applies, or -1 if there is none
+	previous := func(pos int) int {
+		for pos--; pos >= 0 && !w.active(pos, answers); pos-- {
+		}
+		return pos
+	}
+
+	// Resumed runs continue at the first step without an answer
+	pos := next(-1)
+	for pos < len(w.Steps) {
+		if _, ok := answers[w.Steps[pos].Field.name]; !ok {
+			break
+		}
+		pos = next(pos)
+	}
+
+	for pos < len(w.Steps) {
+		field := w.Steps[pos].Field
+
+		render(pos)
+
+		value, ok := answers[field.name]
+		if !ok {
+			value = field.value
+		}
+
+		back := previous(pos) >= 0
+		value, err := field.ask(promptContext{screen: screen, back: back}, value)
+
+		if errors.Is(err, errBack) {
+			header.clear()
+			pos = previous(pos)
+			continue
+		}
+
+		if err != nil {
+			if errors.Is(err, ErrUserAborted) && w.StatePath != "" && len(answers) > 0 {
+				if err := w.saveState(answers); err != nil {
+					return nil, err
+				}
+			}
+
+			return nil, err
+		}
+
+		screen.clear()
+		header.clear()
+
+		if old, ok := answers[field.name]; ok && !reflect.DeepEqual(old, value) {
+			w.resetDependents(field.name, answers)
+		}
+
+		answers[field.name] = value
+		pos = next(pos)
+	}
+
+	// Leave the answered steps on screen
+	render(len(w.Steps))
+
+	if w.StatePath != "" {
+		os.Remove(w.StatePath)
+	}
+
+	// Answers of steps that don't apply anymore, e.g. after going back and changing a branch, are dropped
+	result := Answers{}
+	for i, step := range w.Steps {
+		if value, ok := answers[step.Field.name]; ok && w.active(i, answers) {
+			result[step.Field.name] = value
+		}
+	}
+
+	return result, nil
+}
+
+func (w *Wizard) MustRun() Answers {
+	answers, err := w.Run()
+
+	if err != nil {
+		Fatal(err)
+	}
+
+	return answers
+}